@@ -96,6 +96,7 @@ func Run() {
 
 	// Initialize k8s API clients
 	kubeClient, chopClient := chop.GetClientset(kubeConfigFile, masterURL)
+	dynamicClient := chop.GetDynamicClient(kubeConfigFile, masterURL)
 
 	// Create operator instance
 	chop.New(kubeClient, chopClient, chopConfigFile)
@@ -118,6 +119,7 @@ func Run() {
 	chiController := chi.NewController(
 		chopClient,
 		kubeClient,
+		dynamicClient,
 		chopInformerFactory,
 		kubeInformerFactory,
 	)
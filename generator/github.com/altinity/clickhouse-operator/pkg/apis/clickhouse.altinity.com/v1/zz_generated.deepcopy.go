@@ -22,6 +22,7 @@ package v1
 
 import (
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -79,6 +80,11 @@ func (in *ChiCluster) DeepCopyInto(out *ChiCluster) {
 		*out = new(ChiClusterLayout)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(ChiClusterSecret)
+		(*in).DeepCopyInto(*out)
+	}
 	out.Address = in.Address
 	if in.CHI != nil {
 		in, out := &in.CHI, &out.CHI
@@ -149,6 +155,48 @@ func (in *ChiClusterLayout) DeepCopy() *ChiClusterLayout {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiClusterSecret) DeepCopyInto(out *ChiClusterSecret) {
+	*out = *in
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = new(ChiClusterSecretSource)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiClusterSecret.
+func (in *ChiClusterSecret) DeepCopy() *ChiClusterSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiClusterSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiClusterSecretSource) DeepCopyInto(out *ChiClusterSecretSource) {
+	*out = *in
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiClusterSecretSource.
+func (in *ChiClusterSecretSource) DeepCopy() *ChiClusterSecretSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiClusterSecretSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChiDefaults) DeepCopyInto(out *ChiDefaults) {
 	*out = *in
@@ -356,6 +404,7 @@ func (in *ChiPodTemplate) DeepCopyInto(out *ChiPodTemplate) {
 		*out = make([]ChiPodDistribution, len(*in))
 		copy(*out, *in)
 	}
+	in.Warmup.DeepCopyInto(&out.Warmup)
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
 	return
@@ -371,6 +420,27 @@ func (in *ChiPodTemplate) DeepCopy() *ChiPodTemplate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiPodTemplateWarmup) DeepCopyInto(out *ChiPodTemplateWarmup) {
+	*out = *in
+	if in.Queries != nil {
+		in, out := &in.Queries, &out.Queries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiPodTemplateWarmup.
+func (in *ChiPodTemplateWarmup) DeepCopy() *ChiPodTemplateWarmup {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiPodTemplateWarmup)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChiPodTemplateZone) DeepCopyInto(out *ChiPodTemplateZone) {
 	*out = *in
@@ -429,6 +499,22 @@ func (in *ChiReconciling) DeepCopy() *ChiReconciling {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChiQueryMaskingRule) DeepCopyInto(out *ChiQueryMaskingRule) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChiQueryMaskingRule.
+func (in *ChiQueryMaskingRule) DeepCopy() *ChiQueryMaskingRule {
+	if in == nil {
+		return nil
+	}
+	out := new(ChiQueryMaskingRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChiReplica) DeepCopyInto(out *ChiReplica) {
 	*out = *in
@@ -1059,6 +1145,16 @@ func (in *Configuration) DeepCopyInto(out *Configuration) {
 		*out = new(Settings)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.UserTemplates != nil {
+		in, out := &in.UserTemplates, &out.UserTemplates
+		*out = new(Settings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.QueryMaskingRules != nil {
+		in, out := &in.QueryMaskingRules, &out.QueryMaskingRules
+		*out = make([]ChiQueryMaskingRule, len(*in))
+		copy(*out, *in)
+	}
 	if in.Clusters != nil {
 		in, out := &in.Clusters, &out.Clusters
 		*out = make([]*ChiCluster, len(*in))
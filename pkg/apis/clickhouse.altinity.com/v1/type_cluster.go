@@ -22,6 +22,7 @@ type ChiCluster struct {
 	Files     *Settings           `json:"files,omitempty"     yaml:"files,omitempty"`
 	Templates *ChiTemplateNames   `json:"templates,omitempty" yaml:"templates,omitempty"`
 	Layout    *ChiClusterLayout   `json:"layout,omitempty"    yaml:"layout,omitempty"`
+	Secret    *ChiClusterSecret   `json:"secret,omitempty"    yaml:"secret,omitempty"`
 
 	// Internal data
 	Address ChiClusterAddress       `json:"-" yaml:"-"`
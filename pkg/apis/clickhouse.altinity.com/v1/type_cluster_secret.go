@@ -0,0 +1,73 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ChiClusterSecret defines interserver secret of a cluster, used by replicas to authenticate each other
+type ChiClusterSecret struct {
+	// Value specifies plaintext secret value
+	Value string `json:"value,omitempty"     yaml:"value,omitempty"`
+	// ValueFrom specifies k8s Secret to read secret value from, has priority over Value.
+	// In case specified k8s Secret can not be read, reconcile fails, unless the ref is optional
+	ValueFrom *ChiClusterSecretSource `json:"valueFrom,omitempty" yaml:"valueFrom,omitempty"`
+
+	// Internal data
+	// Resolved is the secret value to be used in config, fetched either from Value or from ValueFrom
+	Resolved string `json:"-" yaml:"-"`
+}
+
+// ChiClusterSecretSource defines source of a cluster secret
+type ChiClusterSecretSource struct {
+	// SecretKeyRef refers to a key of a k8s Secret in the namespace of the CHI
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty" yaml:"secretKeyRef,omitempty"`
+}
+
+// HasSecretKeyRef checks whether secret value is to be read from k8s Secret
+func (s *ChiClusterSecret) HasSecretKeyRef() bool {
+	if s == nil {
+		return false
+	}
+	if s.ValueFrom == nil {
+		return false
+	}
+	return s.ValueFrom.SecretKeyRef != nil
+}
+
+// IsOptional checks whether k8s Secret the value is read from is allowed to be missing
+func (s *ChiClusterSecret) IsOptional() bool {
+	if !s.HasSecretKeyRef() {
+		return false
+	}
+	if s.ValueFrom.SecretKeyRef.Optional == nil {
+		return false
+	}
+	return *s.ValueFrom.SecretKeyRef.Optional
+}
+
+// IsUnresolved checks whether secret value is expected to be read from k8s Secret, but it is not available
+func (s *ChiClusterSecret) IsUnresolved() bool {
+	return s.HasSecretKeyRef() && !s.IsOptional() && (s.GetResolved() == "")
+}
+
+// GetResolved gets secret value to be used in config
+func (s *ChiClusterSecret) GetResolved() string {
+	if s == nil {
+		return ""
+	}
+	return s.Resolved
+}
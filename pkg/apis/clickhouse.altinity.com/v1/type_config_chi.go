@@ -36,6 +36,10 @@ type Configuration struct {
 	Quotas    *Settings           `json:"quotas,omitempty"    yaml:"quotas,omitempty"`
 	Settings  *Settings           `json:"settings,omitempty"  yaml:"settings,omitempty"`
 	Files     *Settings           `json:"files,omitempty"     yaml:"files,omitempty"`
+	// User templates are base definitions of users, which users can inherit with 'username/template: name'
+	UserTemplates *Settings `json:"userTemplates,omitempty" yaml:"userTemplates,omitempty"`
+	// Query masking rules are used to hide sensitive data in logs
+	QueryMaskingRules []ChiQueryMaskingRule `json:"queryMaskingRules,omitempty" yaml:"queryMaskingRules,omitempty"`
 	// TODO refactor into map[string]ChiCluster
 	Clusters []*ChiCluster `json:"clusters,omitempty"  yaml:"clusters,omitempty"`
 }
@@ -57,10 +61,12 @@ func (configuration *Configuration) MergeFrom(from *Configuration, _type MergeTy
 
 	configuration.Zookeeper = configuration.Zookeeper.MergeFrom(from.Zookeeper, _type)
 	configuration.Users = configuration.Users.MergeFrom(from.Users)
+	configuration.UserTemplates = configuration.UserTemplates.MergeFrom(from.UserTemplates)
 	configuration.Profiles = configuration.Profiles.MergeFrom(from.Profiles)
 	configuration.Quotas = configuration.Quotas.MergeFrom(from.Quotas)
 	configuration.Settings = configuration.Settings.MergeFrom(from.Settings)
 	configuration.Files = configuration.Files.MergeFrom(from.Files)
+	configuration.QueryMaskingRules = mergeQueryMaskingRules(configuration.QueryMaskingRules, from.QueryMaskingRules)
 
 	// TODO merge clusters
 	// Copy Clusters for now
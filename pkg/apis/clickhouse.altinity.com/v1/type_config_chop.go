@@ -28,6 +28,7 @@ import (
 	log "github.com/golang/glog"
 	"github.com/imdario/mergo"
 	"github.com/kubernetes-sigs/yaml"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/altinity/clickhouse-operator/pkg/util"
@@ -48,6 +49,11 @@ const (
 	defaultChConfigUserDefaultNetworksIP = "::/0"
 	defaultChConfigUserDefaultPassword   = "default"
 
+	// defaultChConfigMacroInstallationNamespaceSuffix specifies default value for CHConfigMacroInstallationNamespaceSuffix
+	defaultChConfigMacroInstallationNamespaceSuffix = "no"
+	// defaultChConfigValidateStructure specifies default value for CHConfigValidateStructure
+	defaultChConfigValidateStructure = "no"
+
 	// Username and Password to be used by operator to connect to ClickHouse instances for
 	// 1. Metrics requests
 	// 2. Schema maintenance
@@ -71,6 +77,26 @@ const (
 	defaultTerminationGracePeriod = 30
 	// defaultRevisionHistoryLimit specifies default value for RevisionHistoryLimit
 	defaultRevisionHistoryLimit = 10
+	// defaultTerminationMessagePolicy specifies default value for TerminationMessagePolicy
+	defaultTerminationMessagePolicy = string(corev1.TerminationMessageReadFile)
+	// defaultPodEnableServiceLinks specifies default value for PodEnableServiceLinks
+	defaultPodEnableServiceLinks = "no"
+	// defaultImagePullPolicy specifies default value for ImagePullPolicy
+	defaultImagePullPolicy = string(corev1.PullIfNotPresent)
+	// defaultDebugSidecarImage specifies default value for DebugSidecarImage
+	defaultDebugSidecarImage = "yandex/clickhouse-client:20.7"
+
+	// defaultCHIServicePublishNotReadyAddresses specifies default value for CHIServicePublishNotReadyAddresses
+	defaultCHIServicePublishNotReadyAddresses = "no"
+	// defaultHostServicePublishNotReadyAddresses specifies default value for HostServicePublishNotReadyAddresses
+	defaultHostServicePublishNotReadyAddresses = "yes"
+	// defaultClusterService specifies default value for ClusterService
+	defaultClusterService = "no"
+
+	// defaultCHIPodMonitor specifies default value for CHIPodMonitor
+	defaultCHIPodMonitor = "no"
+	// defaultHostMetricsService specifies default value for HostMetricsService
+	defaultHostMetricsService = "no"
 )
 
 // OperatorConfig specifies operator configuration
@@ -132,6 +158,17 @@ type OperatorConfig struct {
 
 	CHConfigNetworksHostRegexpTemplate string `json:"chConfigNetworksHostRegexpTemplate" yaml:"chConfigNetworksHostRegexpTemplate"`
 
+	// Whether to append namespace-derived suffix to <installation> macro and distributed DDL path,
+	// so identically named CHIs in different namespaces do not collide in ZK paths
+	CHConfigMacroInstallationNamespaceSuffixString string `json:"chConfigMacroInstallationNamespaceSuffix" yaml:"chConfigMacroInstallationNamespaceSuffix"`
+	CHConfigMacroInstallationNamespaceSuffix       bool
+
+	// Whether generated config files with known structure, such as remote_servers and users, should fail reconcile
+	// in case of unexpected nesting. Otherwise structural issues are reported as warnings only.
+	// Malformed XML always fails reconcile
+	CHConfigValidateStructureString string `json:"chConfigValidateStructure" yaml:"chConfigValidateStructure"`
+	CHConfigValidateStructure       bool
+
 	// Username and Password to be used by operator to connect to ClickHouse instances
 	// for
 	// 1. Metrics requests
@@ -176,10 +213,44 @@ type OperatorConfig struct {
 	AppendScopeLabelsString string `json:"appendScopeLabels" yaml:"appendScopeLabels"`
 	AppendScopeLabels       bool
 
+	// Additional labels to be set on StatefulSets, ex.: to be selected by external autoscalers as scale targets.
+	// Macros are expanded in values.
+	StatefulSetLabels map[string]string `json:"statefulSetLabels" yaml:"statefulSetLabels"`
+
 	// Grace period for Pod termination.
 	TerminationGracePeriod int `json:"terminationGracePeriod" yaml:"terminationGracePeriod"`
 	// Revision history limit
 	RevisionHistoryLimit int `json:"revisionHistoryLimit" yaml:"revisionHistoryLimit"`
+	// Termination message policy of ClickHouse container
+	TerminationMessagePolicy string `json:"terminationMessagePolicy" yaml:"terminationMessagePolicy"`
+	// Image pull policy of ClickHouse container
+	ImagePullPolicy string `json:"imagePullPolicy" yaml:"imagePullPolicy"`
+	// Whether information about Services should be injected into Pod's environment variables
+	PodEnableServiceLinksString string `json:"podEnableServiceLinks" yaml:"podEnableServiceLinks"`
+	PodEnableServiceLinks       bool
+	// Image of debug sidecar container, requested by debugSidecar of podTemplate
+	DebugSidecarImage string `json:"debugSidecarImage" yaml:"debugSidecarImage"`
+
+	// IP family policy and IP families for Services. Empty means cluster default.
+	ServiceIPFamilyPolicy string   `json:"serviceIPFamilyPolicy" yaml:"serviceIPFamilyPolicy"`
+	ServiceIPFamilies     []string `json:"serviceIPFamilies"     yaml:"serviceIPFamilies"`
+	// Whether to publish not ready addresses on client-facing CHI Service and headless per-host Service.
+	CHIServicePublishNotReadyAddressesString  string `json:"chiServicePublishNotReadyAddresses"  yaml:"chiServicePublishNotReadyAddresses"`
+	CHIServicePublishNotReadyAddresses        bool
+	HostServicePublishNotReadyAddressesString string `json:"hostServicePublishNotReadyAddresses" yaml:"hostServicePublishNotReadyAddresses"`
+	HostServicePublishNotReadyAddresses       bool
+	// Whether to allocate NodePorts for client-facing CHI Service of LoadBalancer type. Empty means cluster default.
+	CHIServiceAllocateLoadBalancerNodePorts string `json:"chiServiceAllocateLoadBalancerNodePorts" yaml:"chiServiceAllocateLoadBalancerNodePorts"`
+	// Whether to create default per-cluster Service for clusters without clusterServiceTemplate
+	ClusterServiceString string `json:"clusterService" yaml:"clusterService"`
+	ClusterService       bool
+
+	// Whether to create Prometheus Operator PodMonitor for CHI with prometheus endpoint configured
+	CHIPodMonitorString string `json:"chiPodMonitor" yaml:"chiPodMonitor"`
+	CHIPodMonitor       bool
+	// Whether to create metrics-only ClusterIP Service for each host with prometheus endpoint configured
+	HostMetricsServiceString string `json:"hostMetricsService" yaml:"hostMetricsService"`
+	HostMetricsService       bool
 	//
 	// The end of OperatorConfig
 	//
@@ -467,6 +538,16 @@ func (config *OperatorConfig) normalizeSettingsSection() {
 	}
 
 	// chConfigNetworksHostRegexpTemplate
+
+	if config.CHConfigMacroInstallationNamespaceSuffixString == "" {
+		config.CHConfigMacroInstallationNamespaceSuffixString = defaultChConfigMacroInstallationNamespaceSuffix
+	}
+	config.CHConfigMacroInstallationNamespaceSuffix = util.IsStringBoolTrue(config.CHConfigMacroInstallationNamespaceSuffixString)
+
+	if config.CHConfigValidateStructureString == "" {
+		config.CHConfigValidateStructureString = defaultChConfigValidateStructure
+	}
+	config.CHConfigValidateStructure = util.IsStringBoolTrue(config.CHConfigValidateStructureString)
 }
 
 func (config *OperatorConfig) normalizeAccessSection() {
@@ -529,6 +610,48 @@ func (config *OperatorConfig) normalizePodManagementSection() {
 	if config.RevisionHistoryLimit == 0 {
 		config.RevisionHistoryLimit = defaultRevisionHistoryLimit
 	}
+	if config.TerminationMessagePolicy == "" {
+		config.TerminationMessagePolicy = defaultTerminationMessagePolicy
+	}
+	if config.ImagePullPolicy == "" {
+		config.ImagePullPolicy = defaultImagePullPolicy
+	}
+	if config.PodEnableServiceLinksString == "" {
+		config.PodEnableServiceLinksString = defaultPodEnableServiceLinks
+	}
+	config.PodEnableServiceLinks = util.IsStringBoolTrue(config.PodEnableServiceLinksString)
+	if config.DebugSidecarImage == "" {
+		config.DebugSidecarImage = defaultDebugSidecarImage
+	}
+}
+
+func (config *OperatorConfig) normalizeServiceManagementSection() {
+	if config.CHIServicePublishNotReadyAddressesString == "" {
+		config.CHIServicePublishNotReadyAddressesString = defaultCHIServicePublishNotReadyAddresses
+	}
+	config.CHIServicePublishNotReadyAddresses = util.IsStringBoolTrue(config.CHIServicePublishNotReadyAddressesString)
+
+	if config.HostServicePublishNotReadyAddressesString == "" {
+		config.HostServicePublishNotReadyAddressesString = defaultHostServicePublishNotReadyAddresses
+	}
+	config.HostServicePublishNotReadyAddresses = util.IsStringBoolTrue(config.HostServicePublishNotReadyAddressesString)
+
+	if config.ClusterServiceString == "" {
+		config.ClusterServiceString = defaultClusterService
+	}
+	config.ClusterService = util.IsStringBoolTrue(config.ClusterServiceString)
+}
+
+func (config *OperatorConfig) normalizeMonitoringSection() {
+	if config.CHIPodMonitorString == "" {
+		config.CHIPodMonitorString = defaultCHIPodMonitor
+	}
+	config.CHIPodMonitor = util.IsStringBoolTrue(config.CHIPodMonitorString)
+
+	if config.HostMetricsServiceString == "" {
+		config.HostMetricsServiceString = defaultHostMetricsService
+	}
+	config.HostMetricsService = util.IsStringBoolTrue(config.HostMetricsServiceString)
 }
 
 // normalize() makes fully-and-correctly filled OperatorConfig
@@ -543,6 +666,8 @@ func (config *OperatorConfig) normalize() {
 	config.normalizeRuntimeSection()
 	config.normalizeLabelsSection()
 	config.normalizePodManagementSection()
+	config.normalizeServiceManagementSection()
+	config.normalizeMonitoringSection()
 }
 
 // applyEnvVarParams applies ENV VARS over config
@@ -659,6 +784,8 @@ func (config *OperatorConfig) String(hideCredentials bool) string {
 	}
 	util.Fprintf(b, "CHConfigUserDefaultPassword: %s\n", password)
 	util.Fprintf(b, "CHConfigNetworksHostRegexpTemplate: %s\n", config.CHConfigNetworksHostRegexpTemplate)
+	util.Fprintf(b, "CHConfigMacroInstallationNamespaceSuffix: %s (%t)\n", config.CHConfigMacroInstallationNamespaceSuffixString, config.CHConfigMacroInstallationNamespaceSuffix)
+	util.Fprintf(b, "CHConfigValidateStructure: %s (%t)\n", config.CHConfigValidateStructureString, config.CHConfigValidateStructure)
 
 	username = config.CHUsername
 	password = config.CHPassword
@@ -697,8 +824,23 @@ func (config *OperatorConfig) String(hideCredentials bool) string {
 	util.Fprintf(b, "%s", util.Slice2String("IncludeIntoPropagationLabels", config.IncludeIntoPropagationLabels))
 	util.Fprintf(b, "%s", util.Slice2String("ExcludeFromPropagationLabels", config.ExcludeFromPropagationLabels))
 	util.Fprintf(b, "appendScopeLabels: %s (%t)\n", config.AppendScopeLabelsString, config.AppendScopeLabels)
+	util.Fprintf(b, "%s", util.Map2String("statefulSetLabels", config.StatefulSetLabels))
 
 	util.Fprintf(b, "terminationGracePeriod: %d\n", config.TerminationGracePeriod)
+	util.Fprintf(b, "terminationMessagePolicy: %s\n", config.TerminationMessagePolicy)
+	util.Fprintf(b, "imagePullPolicy: %s\n", config.ImagePullPolicy)
+	util.Fprintf(b, "podEnableServiceLinks: %s (%t)\n", config.PodEnableServiceLinksString, config.PodEnableServiceLinks)
+	util.Fprintf(b, "debugSidecarImage: %s\n", config.DebugSidecarImage)
+
+	util.Fprintf(b, "serviceIPFamilyPolicy: %s\n", config.ServiceIPFamilyPolicy)
+	util.Fprintf(b, "%s", util.Slice2String("serviceIPFamilies", config.ServiceIPFamilies))
+	util.Fprintf(b, "chiServicePublishNotReadyAddresses: %s (%t)\n", config.CHIServicePublishNotReadyAddressesString, config.CHIServicePublishNotReadyAddresses)
+	util.Fprintf(b, "hostServicePublishNotReadyAddresses: %s (%t)\n", config.HostServicePublishNotReadyAddressesString, config.HostServicePublishNotReadyAddresses)
+	util.Fprintf(b, "chiServiceAllocateLoadBalancerNodePorts: %s\n", config.CHIServiceAllocateLoadBalancerNodePorts)
+	util.Fprintf(b, "clusterService: %s (%t)\n", config.ClusterServiceString, config.ClusterService)
+
+	util.Fprintf(b, "chiPodMonitor: %s (%t)\n", config.CHIPodMonitorString, config.CHIPodMonitor)
+	util.Fprintf(b, "hostMetricsService: %s (%t)\n", config.HostMetricsServiceString, config.HostMetricsService)
 
 	return b.String()
 }
@@ -762,3 +904,55 @@ func (config *OperatorConfig) GetRevisionHistoryLimit() *int32 {
 	revisionHistoryLimit := int32(config.RevisionHistoryLimit)
 	return &revisionHistoryLimit
 }
+
+// GetTerminationMessagePolicy gets terminationMessagePolicy, as expected by
+// container.TerminationMessagePolicy
+func (config *OperatorConfig) GetTerminationMessagePolicy() corev1.TerminationMessagePolicy {
+	return corev1.TerminationMessagePolicy(config.TerminationMessagePolicy)
+}
+
+// GetImagePullPolicy gets imagePullPolicy, as expected by
+// container.ImagePullPolicy
+func (config *OperatorConfig) GetImagePullPolicy() corev1.PullPolicy {
+	return corev1.PullPolicy(config.ImagePullPolicy)
+}
+
+// GetPodEnableServiceLinks gets pointer to podEnableServiceLinks, as expected by
+// statefulSet.Spec.Template.Spec.EnableServiceLinks
+func (config *OperatorConfig) GetPodEnableServiceLinks() *bool {
+	enableServiceLinks := config.PodEnableServiceLinks
+	return &enableServiceLinks
+}
+
+// GetServiceIPFamilyPolicy gets pointer to serviceIPFamilyPolicy, as expected by
+// service.Spec.IPFamilyPolicy. Returns nil in case cluster default is to be used
+func (config *OperatorConfig) GetServiceIPFamilyPolicy() *corev1.IPFamilyPolicyType {
+	if config.ServiceIPFamilyPolicy == "" {
+		return nil
+	}
+	ipFamilyPolicy := corev1.IPFamilyPolicyType(config.ServiceIPFamilyPolicy)
+	return &ipFamilyPolicy
+}
+
+// GetCHIServiceAllocateLoadBalancerNodePorts gets pointer to chiServiceAllocateLoadBalancerNodePorts, as expected by
+// service.Spec.AllocateLoadBalancerNodePorts. Returns nil in case cluster default is to be used
+func (config *OperatorConfig) GetCHIServiceAllocateLoadBalancerNodePorts() *bool {
+	if config.CHIServiceAllocateLoadBalancerNodePorts == "" {
+		return nil
+	}
+	allocate := util.IsStringBoolTrue(config.CHIServiceAllocateLoadBalancerNodePorts)
+	return &allocate
+}
+
+// GetServiceIPFamilies gets serviceIPFamilies, as expected by
+// service.Spec.IPFamilies. Returns nil in case cluster default is to be used
+func (config *OperatorConfig) GetServiceIPFamilies() []corev1.IPFamily {
+	if len(config.ServiceIPFamilies) == 0 {
+		return nil
+	}
+	ipFamilies := make([]corev1.IPFamily, 0, len(config.ServiceIPFamilies))
+	for _, ipFamily := range config.ServiceIPFamilies {
+		ipFamilies = append(ipFamilies, corev1.IPFamily(ipFamily))
+	}
+	return ipFamilies
+}
@@ -29,9 +29,18 @@ type ChiHost struct {
 	TCPPort             int32             `json:"tcpPort,omitempty"             yaml:"tcpPort,omitempty"`
 	HTTPPort            int32             `json:"httpPort,omitempty"            yaml:"httpPort,omitempty"`
 	InterserverHTTPPort int32             `json:"interserverHTTPPort,omitempty" yaml:"interserverHTTPPort,omitempty"`
+	MySQLPort           int32             `json:"mysqlPort,omitempty"           yaml:"mysqlPort,omitempty"`
+	PostgreSQLPort      int32             `json:"postgresqlPort,omitempty"      yaml:"postgresqlPort,omitempty"`
 	Settings            *Settings         `json:"settings,omitempty"            yaml:"settings,omitempty"`
 	Files               *Settings         `json:"files,omitempty"               yaml:"files,omitempty"`
 	Templates           *ChiTemplateNames `json:"templates,omitempty"           yaml:"templates,omitempty"`
+	// DataVolumeStorage overrides storage request of data volume claim template for the host
+	DataVolumeStorage string `json:"dataVolumeStorage,omitempty" yaml:"dataVolumeStorage,omitempty"`
+	// Priority of the host in remote_servers
+	Priority int `json:"priority,omitempty" yaml:"priority,omitempty"`
+	// Partition of the StatefulSet rolling update. Host's StatefulSet has exactly one pod,
+	// so 1 holds the host on its current revision, 0 lets it be updated
+	Partition int32 `json:"partition,omitempty" yaml:"partition,omitempty"`
 
 	// Internal data
 	Address             ChiHostAddress             `json:"-" yaml:"-"`
@@ -82,6 +91,36 @@ func (host *ChiHost) InheritTemplatesFrom(shard *ChiShard, replica *ChiReplica,
 	host.Templates.HandleDeprecatedFields()
 }
 
+// InheritDataVolumeStorageFrom inherits data volume storage override from specified replica
+func (host *ChiHost) InheritDataVolumeStorageFrom(replica *ChiReplica) {
+	if replica == nil {
+		return
+	}
+	if host.DataVolumeStorage == "" {
+		host.DataVolumeStorage = replica.DataVolumeStorage
+	}
+}
+
+// InheritPriorityFrom inherits priority from specified replica
+func (host *ChiHost) InheritPriorityFrom(replica *ChiReplica) {
+	if replica == nil {
+		return
+	}
+	if host.Priority == 0 {
+		host.Priority = replica.Priority
+	}
+}
+
+// InheritPartitionFrom inherits rolling update partition from specified replica
+func (host *ChiHost) InheritPartitionFrom(replica *ChiReplica) {
+	if replica == nil {
+		return
+	}
+	if host.Partition == 0 {
+		host.Partition = replica.Partition
+	}
+}
+
 // MergeFrom merges from specified host
 func (host *ChiHost) MergeFrom(from *ChiHost) {
 	if (host == nil) || (from == nil) {
@@ -100,6 +139,12 @@ func (host *ChiHost) MergeFrom(from *ChiHost) {
 	if host.InterserverHTTPPort == 0 {
 		host.InterserverHTTPPort = from.InterserverHTTPPort
 	}
+	if host.MySQLPort == 0 {
+		host.MySQLPort = from.MySQLPort
+	}
+	if host.PostgreSQLPort == 0 {
+		host.PostgreSQLPort = from.PostgreSQLPort
+	}
 	host.Templates = host.Templates.MergeFrom(from.Templates, MergeTypeFillEmptyValues)
 	host.Templates.HandleDeprecatedFields()
 }
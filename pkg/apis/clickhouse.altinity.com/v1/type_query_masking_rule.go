@@ -0,0 +1,54 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+// ChiQueryMaskingRule defines item of .spec.configuration.queryMaskingRules
+// Each rule is rendered as <query_masking_rules><rule>...</rule></query_masking_rules>
+type ChiQueryMaskingRule struct {
+	Name    string `json:"name,omitempty"    yaml:"name,omitempty"`
+	Regexp  string `json:"regexp,omitempty"  yaml:"regexp,omitempty"`
+	Replace string `json:"replace,omitempty" yaml:"replace,omitempty"`
+}
+
+// IsEmpty checks whether rule has no regexp specified
+func (rule *ChiQueryMaskingRule) IsEmpty() bool {
+	if rule == nil {
+		return true
+	}
+	return rule.Regexp == ""
+}
+
+// mergeQueryMaskingRules appends rules from `from` which are not listed in `to` by name
+func mergeQueryMaskingRules(to, from []ChiQueryMaskingRule) []ChiQueryMaskingRule {
+	for i := range from {
+		fromRule := &from[i]
+
+		// Try to find rule with the same name
+		found := false
+		for j := range to {
+			toRule := &to[j]
+			if (fromRule.Name != "") && (toRule.Name == fromRule.Name) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			to = append(to, *fromRule)
+		}
+	}
+
+	return to
+}
@@ -394,6 +394,21 @@ func (settings *Settings) GetInterserverHTTPPort() int32 {
 	return settings.fetchPort("interserver_http_port")
 }
 
+// GetMySQLPort gets MySQL port from settings
+func (settings *Settings) GetMySQLPort() int32 {
+	return settings.fetchPort("mysql_port")
+}
+
+// GetPostgreSQLPort gets PostgreSQL port from settings
+func (settings *Settings) GetPostgreSQLPort() int32 {
+	return settings.fetchPort("postgresql_port")
+}
+
+// GetPrometheusPort gets Prometheus endpoint port from settings
+func (settings *Settings) GetPrometheusPort() int32 {
+	return settings.fetchPort("prometheus/port")
+}
+
 // MergeFrom merges into `dst` non-empty new-key-values from `src` in case no such `key` already in `src`
 func (settings *Settings) MergeFrom(src *Settings) *Settings {
 	if src.Len() == 0 {
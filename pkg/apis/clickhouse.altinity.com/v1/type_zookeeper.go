@@ -64,17 +64,39 @@ func (zkc *ChiZookeeperConfig) MergeFrom(from *ChiZookeeperConfig, _type MergeTy
 		}
 	}
 
-	if from.SessionTimeoutMs > 0 {
-		zkc.SessionTimeoutMs = from.SessionTimeoutMs
-	}
-	if from.OperationTimeoutMs > 0 {
-		zkc.OperationTimeoutMs = from.OperationTimeoutMs
-	}
-	if from.Root != "" {
-		zkc.Root = from.Root
-	}
-	if from.Identity != "" {
-		zkc.Identity = from.Identity
+	switch _type {
+	case MergeTypeFillEmptyValues:
+		if zkc.SessionTimeoutMs == 0 {
+			zkc.SessionTimeoutMs = from.SessionTimeoutMs
+		}
+		if zkc.OperationTimeoutMs == 0 {
+			zkc.OperationTimeoutMs = from.OperationTimeoutMs
+		}
+		if zkc.Root == "" {
+			zkc.Root = from.Root
+		}
+		if zkc.Identity == "" {
+			zkc.Identity = from.Identity
+		}
+		if zkc.LoadBalancing == "" {
+			zkc.LoadBalancing = from.LoadBalancing
+		}
+	case MergeTypeOverrideByNonEmptyValues:
+		if from.SessionTimeoutMs > 0 {
+			zkc.SessionTimeoutMs = from.SessionTimeoutMs
+		}
+		if from.OperationTimeoutMs > 0 {
+			zkc.OperationTimeoutMs = from.OperationTimeoutMs
+		}
+		if from.Root != "" {
+			zkc.Root = from.Root
+		}
+		if from.Identity != "" {
+			zkc.Identity = from.Identity
+		}
+		if from.LoadBalancing != "" {
+			zkc.LoadBalancing = from.LoadBalancing
+		}
 	}
 
 	return zkc
@@ -19,6 +19,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"strings"
 	"time"
+
+	"github.com/altinity/clickhouse-operator/pkg/util"
 )
 
 // MergeType specifies merge types type
@@ -523,6 +525,12 @@ type ChiReplica struct {
 	Files       *Settings         `json:"files,omitempty"       yaml:"files,omitempty"`
 	Templates   *ChiTemplateNames `json:"templates,omitempty"   yaml:"templates,omitempty"`
 	ShardsCount int               `json:"shardsCount,omitempty" yaml:"shardsCount,omitempty"`
+	// DataVolumeStorage overrides storage request of data volume claim template for all hosts of the replica
+	DataVolumeStorage string `json:"dataVolumeStorage,omitempty" yaml:"dataVolumeStorage,omitempty"`
+	// Priority of all hosts of the replica in remote_servers
+	Priority int `json:"priority,omitempty" yaml:"priority,omitempty"`
+	// Partition of the StatefulSet rolling update for all hosts of the replica, 1 holds hosts on their current revision
+	Partition int32 `json:"partition,omitempty" yaml:"partition,omitempty"`
 	// TODO refactor into map[string]ChiHost
 	Hosts []*ChiHost `json:"shards,omitempty" yaml:"shards,omitempty"`
 
@@ -569,6 +577,7 @@ type ChiHostConfig struct {
 	ZookeeperFingerprint string `json:"zookeeperfingerprint" yaml:"zookeeperfingerprint"`
 	SettingsFingerprint  string `json:"settingsfingerprint"  yaml:"settingsfingerprint"`
 	FilesFingerprint     string `json:"filesfingerprint"     yaml:"filesfingerprint"`
+	SecretFingerprint    string `json:"secretfingerprint"    yaml:"secretfingerprint"`
 }
 
 // StatefulSetStatus specifies StatefulSet status
@@ -694,8 +703,20 @@ type ChiPodTemplate struct {
 	GenerateName    string               `json:"generateName,omitempty"    yaml:"generateName,omitempty"`
 	Zone            ChiPodTemplateZone   `json:"zone,omitempty"            yaml:"zone,omitempty"`
 	PodDistribution []ChiPodDistribution `json:"podDistribution,omitempty" yaml:"podDistribution,omitempty"`
-	ObjectMeta      metav1.ObjectMeta    `json:"metadata,omitempty"        yaml:"metadata,omitempty"`
-	Spec            corev1.PodSpec       `json:"spec,omitempty"            yaml:"spec,omitempty"`
+	Warmup          ChiPodTemplateWarmup `json:"warmup,omitempty"          yaml:"warmup,omitempty"`
+	// ZookeeperStartupProbe specifies whether ClickHouse container should wait for Zookeeper connectivity in startup probe
+	ZookeeperStartupProbe string `json:"zookeeperStartupProbe,omitempty" yaml:"zookeeperStartupProbe,omitempty"`
+	// AttachedReadinessProbe specifies whether ClickHouse container should be ready only after all databases are attached
+	// and replicated tables are writable
+	AttachedReadinessProbe string `json:"attachedReadinessProbe,omitempty" yaml:"attachedReadinessProbe,omitempty"`
+	// DebugSidecar specifies whether troubleshooting sidecar with clickhouse-client should be added to the pod
+	DebugSidecar string `json:"debugSidecar,omitempty" yaml:"debugSidecar,omitempty"`
+	// GracefulShutdown specifies whether ClickHouse container should stop merges and flush logs before termination
+	GracefulShutdown string `json:"gracefulShutdown,omitempty" yaml:"gracefulShutdown,omitempty"`
+	// GuaranteedQoS specifies whether resource limits of containers should be equal to requests
+	GuaranteedQoS string            `json:"guaranteedQoS,omitempty" yaml:"guaranteedQoS,omitempty"`
+	ObjectMeta    metav1.ObjectMeta `json:"metadata,omitempty"        yaml:"metadata,omitempty"`
+	Spec          corev1.PodSpec    `json:"spec,omitempty"            yaml:"spec,omitempty"`
 }
 
 // ChiPodTemplateZone defines pod template zone
@@ -704,6 +725,69 @@ type ChiPodTemplateZone struct {
 	Values []string `json:"values,omitempty" yaml:"values,omitempty"`
 }
 
+// IsZookeeperStartupProbe checks whether Zookeeper-aware startup probe is requested
+func (template *ChiPodTemplate) IsZookeeperStartupProbe() bool {
+	if template == nil {
+		return false
+	}
+	return util.IsStringBoolTrue(template.ZookeeperStartupProbe)
+}
+
+// IsAttachedReadinessProbe checks whether attach-aware readiness probe is requested
+func (template *ChiPodTemplate) IsAttachedReadinessProbe() bool {
+	if template == nil {
+		return false
+	}
+	return util.IsStringBoolTrue(template.AttachedReadinessProbe)
+}
+
+// IsDebugSidecar checks whether debug sidecar is requested
+func (template *ChiPodTemplate) IsDebugSidecar() bool {
+	if template == nil {
+		return false
+	}
+	return util.IsStringBoolTrue(template.DebugSidecar)
+}
+
+// IsGracefulShutdown checks whether graceful shutdown preStop hook is requested
+func (template *ChiPodTemplate) IsGracefulShutdown() bool {
+	if template == nil {
+		return false
+	}
+	return util.IsStringBoolTrue(template.GracefulShutdown)
+}
+
+// IsGuaranteedQoS checks whether Guaranteed QoS class is requested
+func (template *ChiPodTemplate) IsGuaranteedQoS() bool {
+	if template == nil {
+		return false
+	}
+	return util.IsStringBoolTrue(template.GuaranteedQoS)
+}
+
+// ChiPodTemplateWarmup defines cache-priming queries to be run against ClickHouse after pod start
+type ChiPodTemplateWarmup struct {
+	Queries []string `json:"queries,omitempty" yaml:"queries,omitempty"`
+	// TimeoutSeconds limits how long all queries may run, as pod is not Running until postStart hook completes
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty" yaml:"timeoutSeconds,omitempty"`
+}
+
+// HasQueries checks whether warmup has queries specified
+func (warmup *ChiPodTemplateWarmup) HasQueries() bool {
+	if warmup == nil {
+		return false
+	}
+	return len(warmup.Queries) > 0
+}
+
+// GetTimeoutSeconds gets time limit of warmup queries, falls back to provided default in case not specified
+func (warmup *ChiPodTemplateWarmup) GetTimeoutSeconds(_default int) int {
+	if (warmup == nil) || (warmup.TimeoutSeconds <= 0) {
+		return _default
+	}
+	return warmup.TimeoutSeconds
+}
+
 // ChiPodDistribution defines pod distribution
 type ChiPodDistribution struct {
 	Type        string `json:"type,omitempty"        yaml:"type,omitempty"`
@@ -767,11 +851,12 @@ type ChiDistributedDDL struct {
 // Refers to
 // https://clickhouse.yandex/docs/en/single/index.html?#server-settings_zookeeper
 type ChiZookeeperConfig struct {
-	Nodes              []ChiZookeeperNode `json:"nodes,omitempty"                yaml:"nodes,omitempty"`
-	SessionTimeoutMs   int                `json:"session_timeout_ms,omitempty"   yaml:"session_timeout_ms,omitempty"`
-	OperationTimeoutMs int                `json:"operation_timeout_ms,omitempty" yaml:"operation_timeout_ms,omitempty"`
-	Root               string             `json:"root,omitempty"                 yaml:"root,omitempty"`
-	Identity           string             `json:"identity,omitempty"             yaml:"identity,omitempty"`
+	Nodes              []ChiZookeeperNode `json:"nodes,omitempty"                    yaml:"nodes,omitempty"`
+	SessionTimeoutMs   int                `json:"session_timeout_ms,omitempty"       yaml:"session_timeout_ms,omitempty"`
+	OperationTimeoutMs int                `json:"operation_timeout_ms,omitempty"     yaml:"operation_timeout_ms,omitempty"`
+	Root               string             `json:"root,omitempty"                     yaml:"root,omitempty"`
+	Identity           string             `json:"identity,omitempty"                 yaml:"identity,omitempty"`
+	LoadBalancing      string             `json:"zookeeper_load_balancing,omitempty" yaml:"zookeeper_load_balancing,omitempty"`
 }
 
 // ChiZookeeperNode defines item of nodes section of .spec.configuration.zookeeper
@@ -20,6 +20,7 @@ import (
 	"os/user"
 	"path/filepath"
 
+	"k8s.io/client-go/dynamic"
 	kube "k8s.io/client-go/kubernetes"
 	kuberest "k8s.io/client-go/rest"
 	kubeclientcmd "k8s.io/client-go/tools/clientcmd"
@@ -83,6 +84,22 @@ func GetClientset(kubeConfigFile, masterURL string) (*kube.Clientset, *chopclien
 	return kubeClientset, chopClientset
 }
 
+// GetDynamicClient gets k8s API dynamic client, used to manage objects of third-party CRDs
+func GetDynamicClient(kubeConfigFile, masterURL string) dynamic.Interface {
+	kubeConfig, err := getKubeConfig(kubeConfigFile, masterURL)
+	if err != nil {
+		log.A().Fatal("Unable to build kubeconf: %s", err.Error())
+		os.Exit(1)
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(kubeConfig)
+	if err != nil {
+		log.A().Fatal("Unable to initialize kubernetes API dynamic client: %s", err.Error())
+	}
+
+	return dynamicClient
+}
+
 var chop *CHOp
 
 // New creates chop instance
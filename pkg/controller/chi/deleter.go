@@ -249,6 +249,8 @@ func (c *Controller) deleteServiceHost(ctx context.Context, host *chop.ChiHost)
 	serviceName := chopmodel.CreateStatefulSetServiceName(host)
 	namespace := host.Address.Namespace
 	log.V(1).M(host).F().Info("%s/%s", namespace, serviceName)
+	// Metrics-only Service is optional and may be absent
+	_ = c.deleteServiceIfExists(ctx, namespace, chopmodel.CreateHostMetricsServiceName(host))
 	return c.deleteServiceIfExists(ctx, namespace, serviceName)
 }
 
@@ -291,6 +293,34 @@ func (c *Controller) deleteServiceCHI(ctx context.Context, chi *chop.ClickHouseI
 	return c.deleteServiceIfExists(ctx, namespace, serviceName)
 }
 
+// deletePodMonitorIfExists deletes PodMonitor of the CHI in case it exists
+func (c *Controller) deletePodMonitorIfExists(ctx context.Context, chi *chop.ClickHouseInstallation) error {
+	if util.IsContextDone(ctx) {
+		log.V(2).Info("ctx is done")
+		return nil
+	}
+
+	name := chopmodel.CreatePodMonitorName(chi)
+	namespace := chi.Namespace
+	podMonitors := c.dynamicClient.Resource(chopmodel.PodMonitorGVR).Namespace(namespace)
+
+	// Check specified PodMonitor exists. PodMonitor CRD may be not installed as well
+	if _, err := podMonitors.Get(ctx, name, newGetOptions()); err != nil {
+		// No such a PodMonitor, nothing to delete
+		return nil
+	}
+
+	// Delete PodMonitor
+	err := podMonitors.Delete(ctx, name, newDeleteOptions())
+	if err == nil {
+		log.V(1).M(namespace, name).Info("OK delete PodMonitor %s/%s", namespace, name)
+	} else {
+		log.V(1).M(namespace, name).A().Error("FAIL delete PodMonitor %s/%s err:%v", namespace, name, err)
+	}
+
+	return err
+}
+
 // deleteServiceIfExists deletes Service in case it does not exist
 func (c *Controller) deleteServiceIfExists(ctx context.Context, namespace, name string) error {
 	if util.IsContextDone(ctx) {
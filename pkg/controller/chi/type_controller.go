@@ -17,6 +17,7 @@ package chi
 import (
 	"time"
 
+	"k8s.io/client-go/dynamic"
 	kube "k8s.io/client-go/kubernetes"
 	appslisters "k8s.io/client-go/listers/apps/v1"
 	corelisters "k8s.io/client-go/listers/core/v1"
@@ -34,6 +35,8 @@ import (
 type Controller struct {
 	// kubeClient used to Create() k8s resources as c.kubeClient.AppsV1().StatefulSets(namespace).Create(name)
 	kubeClient kube.Interface
+	// dynamicClient used to manage third-party objects, such as Prometheus Operator PodMonitor
+	dynamicClient dynamic.Interface
 	// chopClient used to Update() CRD k8s resource as c.chopClient.ClickhouseV1().ClickHouseInstallations(chi.Namespace).Update(chiCopy)
 	chopClient chopclientset.Interface
 
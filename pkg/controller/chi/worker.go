@@ -26,6 +26,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	//"github.com/altinity/queue"
 
@@ -293,6 +294,15 @@ func (w *worker) updateCHI(ctx context.Context, old, new *chiv1.ClickHouseInstal
 		return nil
 	}
 
+	if err := w.validate(new); err != nil {
+		// Nothing is touched yet, so CHI stays as it is
+		w.a.WithEvent(new, eventActionReconcile, eventReasonReconcileFailed).
+			WithStatusError(new).
+			M(new).A().
+			Error("FAILED to validate CHI: %v", err)
+		return nil
+	}
+
 	w.markReconcileStart(ctx, new, actionPlan)
 	w.excludeStopped(new)
 	w.walkHosts(new, actionPlan)
@@ -341,6 +351,52 @@ func (w *worker) updateCHI(ctx context.Context, old, new *chiv1.ClickHouseInstal
 	return nil
 }
 
+// validate checks cluster secrets and config files to be generated for the CHI, before any object is touched or any host is excluded,
+// so deterministic config failures do not leave CHI half-reconciled
+func (w *worker) validate(chi *chiv1.ClickHouseInstallation) error {
+	var err error
+	chi.WalkClusters(func(cluster *chiv1.ChiCluster) error {
+		if (err == nil) && cluster.Secret.IsUnresolved() {
+			ref := cluster.Secret.ValueFrom.SecretKeyRef
+			err = fmt.Errorf("cluster %s secret err: unable to read key %s of Secret %s", cluster.Name, ref.Key, ref.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	creator := chopmodel.NewCreator(chi)
+
+	common := creator.CreateConfigMapCHICommon(nil)
+	if err := chopmodel.ValidateConfigFiles(common.Data); err != nil {
+		return fmt.Errorf("common config err: %v", err)
+	}
+	if err := chopmodel.ValidateConfigFiles(creator.CreateConfigMapCHICommonUsers().Data); err != nil {
+		return fmt.Errorf("users config err: %v", err)
+	}
+
+	chi.WalkHosts(func(host *chiv1.ChiHost) error {
+		if err != nil {
+			// Report the first failed host only
+			return nil
+		}
+		personal := creator.CreateConfigMapHost(host)
+		if e := chopmodel.ValidateConfigFiles(personal.Data); e != nil {
+			err = fmt.Errorf("host %s config err: %v", host.Name, e)
+			return nil
+		}
+		// Do not let host refer unknown default storage policy, MergeTree tables would fail to be created.
+		// Effective host config consists of common and personal config files
+		if e := chopmodel.ValidateStoragePolicy(common.Data, personal.Data); e != nil {
+			err = fmt.Errorf("host %s storage policy err: %v", host.Name, e)
+		}
+		return nil
+	})
+
+	return err
+}
+
 func (w *worker) excludeStopped(chi *chiv1.ClickHouseInstallation) {
 	// Exclude stopped CHI from monitoring
 	if chi.IsStopped() {
@@ -635,6 +691,13 @@ func (w *worker) reconcileCHIAuxObjectsPreliminary(ctx context.Context, chi *chi
 	if err := w.reconcileCHIConfigMapUsers(ctx, chi); err != nil {
 		w.a.A().Error("failed to reconcile config map users. err: %v", err)
 	}
+	// 4. CHI PodMonitor
+	if podMonitor := w.creator.CreatePodMonitor(); podMonitor != nil {
+		w.reconcilePodMonitor(ctx, chi, podMonitor)
+	} else {
+		// PodMonitor may be left from the time it was enabled, delete it
+		_ = w.c.deletePodMonitorIfExists(ctx, chi)
+	}
 
 	return nil
 }
@@ -794,6 +857,28 @@ func (w *worker) reconcileHostService(ctx context.Context, host *chiv1.ChiHost)
 	return err
 }
 
+// reconcileHostMetricsService reconciles host's metrics-only Service
+func (w *worker) reconcileHostMetricsService(ctx context.Context, host *chiv1.ChiHost) error {
+	if util.IsContextDone(ctx) {
+		log.V(2).Info("ctx is done")
+		return nil
+	}
+	service := w.creator.CreateServiceHostMetrics(host)
+	if service == nil {
+		// This is not a problem, metrics-only service is optional.
+		// Service may be left from the time it was enabled, delete it
+		_ = w.c.deleteServiceIfExists(ctx, host.Address.Namespace, chopmodel.CreateHostMetricsServiceName(host))
+		return nil
+	}
+	err := w.reconcileService(ctx, host.CHI, service)
+	if err == nil {
+		w.registryReconciled.RegisterService(service.ObjectMeta)
+	} else {
+		w.registryFailed.RegisterService(service.ObjectMeta)
+	}
+	return err
+}
+
 // reconcileCluster reconciles Cluster, excluding nested shards
 func (w *worker) reconcileCluster(ctx context.Context, cluster *chiv1.ChiCluster) error {
 	if util.IsContextDone(ctx) {
@@ -807,7 +892,9 @@ func (w *worker) reconcileCluster(ctx context.Context, cluster *chiv1.ChiCluster
 	// Add Cluster's Service
 	service := w.creator.CreateServiceCluster(cluster)
 	if service == nil {
-		// This is not a problem, ServiceCluster may be omitted
+		// This is not a problem, ServiceCluster may be omitted.
+		// Service may be left from the time default cluster Service was enabled, delete it
+		_ = w.c.deleteServiceCluster(ctx, cluster)
 		return nil
 	}
 	err := w.reconcileService(ctx, cluster.CHI, service)
@@ -879,6 +966,7 @@ func (w *worker) reconcileHost(ctx context.Context, host *chiv1.ChiHost) error {
 	_ = w.reconcilePVCs(ctx, host)
 
 	_ = w.reconcileHostService(ctx, host)
+	_ = w.reconcileHostMetricsService(ctx, host)
 
 	host.ReconcileAttributes.UnsetAdd()
 
@@ -1585,6 +1673,17 @@ func (w *worker) reconcileConfigMap(
 	w.a.V(2).M(chi).S().P()
 	defer w.a.V(2).M(chi).E().P()
 
+	// Do not let malformed config reach ClickHouse, it would refuse to start.
+	// Config files are validated before reconcile starts, this is a backstop only
+	if err := chopmodel.ValidateConfigFiles(configMap.Data); err != nil {
+		w.a.WithEvent(chi, eventActionReconcile, eventReasonReconcileFailed).
+			WithStatusAction(chi).
+			WithStatusError(chi).
+			M(chi).A().
+			Error("FAILED to validate ConfigMap: %s CHI: %s err: %v", configMap.Name, chi.Name, err)
+		return err
+	}
+
 	// Check whether this object already exists in k8s
 	curConfigMap, err := w.c.getConfigMap(&configMap.ObjectMeta, false)
 
@@ -1657,6 +1756,8 @@ func (w *worker) updateService(
 	// See also https://kubernetes.io/docs/concepts/services-networking/service/#virtual-ips-and-service-proxies
 	// You can specify your own cluster IP address as part of a Service creation request. To do this, set the .spec.clusterIP
 	newService.Spec.ClusterIP = curService.Spec.ClusterIP
+	// The same applies to spec.clusterIPs, which is the dual-stack extension of spec.clusterIP
+	newService.Spec.ClusterIPs = curService.Spec.ClusterIPs
 
 	// spec.healthCheckNodePort field is used with ExternalTrafficPolicy=Local only and is immutable within ExternalTrafficPolicy=Local
 	// In case ExternalTrafficPolicy is changed it seems to be irrelevant
@@ -1753,6 +1854,40 @@ func (w *worker) reconcileService(ctx context.Context, chi *chiv1.ClickHouseInst
 	return err
 }
 
+// reconcilePodMonitor reconciles Prometheus Operator PodMonitor.
+// PodMonitor is optional, so failure to reconcile it does not fail CHI reconcile,
+// in particular in case Prometheus Operator CRDs are not installed.
+func (w *worker) reconcilePodMonitor(ctx context.Context, chi *chiv1.ClickHouseInstallation, podMonitor *unstructured.Unstructured) {
+	if util.IsContextDone(ctx) {
+		log.V(2).Info("ctx is done")
+		return
+	}
+
+	w.a.V(2).M(chi).S().Info(podMonitor.GetName())
+	defer w.a.V(2).M(chi).E().Info(podMonitor.GetName())
+
+	podMonitors := w.c.dynamicClient.Resource(chopmodel.PodMonitorGVR).Namespace(podMonitor.GetNamespace())
+
+	// Check whether this object already exists
+	curPodMonitor, err := podMonitors.Get(ctx, podMonitor.GetName(), newGetOptions())
+	switch {
+	case err == nil:
+		// We have PodMonitor - update it
+		podMonitor.SetResourceVersion(curPodMonitor.GetResourceVersion())
+		_, err = podMonitors.Update(ctx, podMonitor, newUpdateOptions())
+	case apierrors.IsNotFound(err):
+		// No PodMonitor - create it
+		_, err = podMonitors.Create(ctx, podMonitor, newCreateOptions())
+	}
+
+	if err != nil {
+		w.a.V(1).M(chi).F().Warning("unable to reconcile PodMonitor: %s/%s err: %v", podMonitor.GetNamespace(), podMonitor.GetName(), err)
+		return
+	}
+
+	w.a.V(1).M(chi).F().Info("PodMonitor reconciled: %s/%s", podMonitor.GetNamespace(), podMonitor.GetName())
+}
+
 // getStatefulSetStatus
 func (w *worker) getStatefulSetStatus(host *chiv1.ChiHost) chiv1.StatefulSetStatus {
 	statefulSet := host.StatefulSet
@@ -2064,16 +2199,17 @@ func (w *worker) reconcilePVC(
 	}
 
 	pvc = w.creator.PreparePersistentVolumeClaim(pvc, host, template)
-	w.applyPVCResourcesRequests(pvc, template)
+	w.applyPVCResourcesRequests(pvc, host, template)
 	return w.c.updatePersistentVolumeClaim(ctx, pvc)
 }
 
-// applyPVCResourcesRequests
+// applyPVCResourcesRequests applies resources requests of the template to PVC, respecting host's overrides
 func (w *worker) applyPVCResourcesRequests(
 	pvc *core.PersistentVolumeClaim,
+	host *chiv1.ChiHost,
 	template *chiv1.ChiVolumeClaimTemplate,
 ) bool {
-	return w.applyResourcesList(pvc.Spec.Resources.Requests, template.Spec.Resources.Requests)
+	return w.applyResourcesList(pvc.Spec.Resources.Requests, w.creator.GetPVCResourcesRequests(host, template))
 }
 
 // applyResourcesList
@@ -25,6 +25,7 @@ const (
 	configPorts         = "ports"
 	configProfiles      = "profiles"
 	configQuotas        = "quotas"
+	configQueryMasking  = "query_masking_rules"
 	configRemoteServers = "remote_servers"
 	configSettings      = "settings"
 	configUsers         = "users"
@@ -55,6 +56,25 @@ const (
 	// 5. operator-provided additional config files
 	dirPathHostConfig = "/etc/clickhouse-server/" + v1.HostConfigDir + "/"
 
+	// configSettingPath specifies name of setting, which overrides path of data folder
+	configSettingPath = "path"
+	// configSettingTmpPath specifies name of setting, which overrides path of folder for temporary data
+	configSettingTmpPath = "tmp_path"
+	// configSettingUserFilesPath specifies name of setting, which overrides path of folder for user files
+	configSettingUserFilesPath = "user_files_path"
+	// configSettingFormatSchemaPath specifies name of setting, which overrides path of folder for format schemas
+	configSettingFormatSchemaPath = "format_schema_path"
+	// configSettingAccessPath specifies name of setting, which overrides path of folder for locally stored access entities
+	configSettingAccessPath = "user_directories/local_directory/path"
+	// configSettingPrometheusEndpoint specifies name of setting, which specifies HTTP path of prometheus endpoint
+	configSettingPrometheusEndpoint = "prometheus/endpoint"
+	// configSettingMergeTreeStoragePolicy specifies name of setting, which specifies default storage policy of MergeTree tables
+	configSettingMergeTreeStoragePolicy = "merge_tree/storage_policy"
+	// configSettingStoragePolicies specifies name of settings section, where storage policies are defined
+	configSettingStoragePolicies = "storage_configuration/policies"
+	// storagePolicyDefault specifies name of storage policy, which is always available in ClickHouse
+	storagePolicyDefault = "default"
+
 	// dirPathClickHouseData specifies full path of data folder where ClickHouse would place its data storage
 	dirPathClickHouseData = "/var/lib/clickhouse"
 
@@ -76,6 +96,8 @@ const (
 	ClickHouseContainerName = "clickhouse"
 	// ClickHouseLogContainerName specifies name of the logger container in the pod
 	ClickHouseLogContainerName = "clickhouse-log"
+	// ClickHouseDebugContainerName specifies name of the debug sidecar container in the pod
+	ClickHouseDebugContainerName = "clickhouse-debug"
 )
 
 const (
@@ -90,6 +112,11 @@ const (
 	chDefaultHTTPPortNumber            = int32(8123)
 	chDefaultInterserverHTTPPortName   = "interserver"
 	chDefaultInterserverHTTPPortNumber = int32(9009)
+
+	// Optional protocols ports names. These protocols are disabled unless port is specified
+	chMySQLPortName      = "mysql"
+	chPostgreSQLPortName = "postgresql"
+	chPrometheusPortName = "prometheus"
 )
 
 const (
@@ -98,3 +125,25 @@ const (
 	// zkDefaultRootTemplate specifies default ZK root - /clickhouse/{namespace}/{chi name}
 	zkDefaultRootTemplate = "/clickhouse/%s/%s"
 )
+
+const (
+	// warmupWaitSeconds specifies how long warmup hook waits for ClickHouse to accept connections
+	warmupWaitSeconds = 300
+	// warmupQueriesTimeoutSeconds specifies default time limit of all warmup queries
+	warmupQueriesTimeoutSeconds = 300
+
+	// zookeeperStartupProbeFailureThreshold specifies how many times (each 10 seconds) Zookeeper-aware
+	// startup probe is retried before container is restarted
+	zookeeperStartupProbeFailureThreshold = 60
+
+	// attachedReadinessProbeTimeoutSeconds specifies how long attach-aware readiness probe waits for tables listing
+	attachedReadinessProbeTimeoutSeconds = 10
+
+	// sidecarCPU specifies CPU request and limit of sidecar containers added by the operator
+	sidecarCPU = "50m"
+	// sidecarMemory specifies memory request and limit of sidecar containers added by the operator
+	sidecarMemory = "64Mi"
+
+	// gracefulShutdownMinSeconds specifies minimal time graceful shutdown hook is allowed to run
+	gracefulShutdownMinSeconds = 1
+)
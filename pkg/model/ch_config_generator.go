@@ -16,9 +16,11 @@ package model
 
 import (
 	"bytes"
+	"encoding/xml"
 	"fmt"
 
 	chiv1 "github.com/altinity/clickhouse-operator/pkg/apis/clickhouse.altinity.com/v1"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
 	xmlbuilder "github.com/altinity/clickhouse-operator/pkg/model/builder/xml"
 	"github.com/altinity/clickhouse-operator/pkg/util"
 )
@@ -137,6 +139,11 @@ func (c *ClickHouseConfigGenerator) GetHostZookeeper(host *chiv1.ChiHost) string
 		util.Iline(b, 8, "<identity>%s</identity>", zk.Identity)
 	}
 
+	// Append zookeeper_load_balancing
+	if len(zk.LoadBalancing) > 0 {
+		util.Iline(b, 8, "<zookeeper_load_balancing>%s</zookeeper_load_balancing>", zk.LoadBalancing)
+	}
+
 	// </zookeeper>
 	util.Iline(b, 4, "</zookeeper>")
 
@@ -156,6 +163,50 @@ func (c *ClickHouseConfigGenerator) GetHostZookeeper(host *chiv1.ChiHost) string
 	return b.String()
 }
 
+// GetQueryMaskingRules creates data for "query_masking_rules.xml"
+func (c *ClickHouseConfigGenerator) GetQueryMaskingRules() string {
+	rules := c.chi.Spec.Configuration.QueryMaskingRules
+	if len(rules) == 0 {
+		// No query masking rules provided
+		return ""
+	}
+
+	b := &bytes.Buffer{}
+	// <yandex>
+	//		<query_masking_rules>
+	util.Iline(b, 0, "<"+xmlTagYandex+">")
+	util.Iline(b, 4, "<query_masking_rules>")
+
+	for i := range rules {
+		// Convenience wrapper
+		rule := &rules[i]
+		if rule.IsEmpty() {
+			continue
+		}
+		// <rule>
+		//		<name>NAME</name>
+		//		<regexp>REGEXP</regexp>
+		//		<replace>REPLACE</replace>
+		// </rule>
+		util.Iline(b, 8, "<rule>")
+		if len(rule.Name) > 0 {
+			util.Iline(b, 8, "    <name>%s</name>", escapeXML(rule.Name))
+		}
+		util.Iline(b, 8, "    <regexp>%s</regexp>", escapeXML(rule.Regexp))
+		if len(rule.Replace) > 0 {
+			util.Iline(b, 8, "    <replace>%s</replace>", escapeXML(rule.Replace))
+		}
+		util.Iline(b, 8, "</rule>")
+	}
+
+	// </query_masking_rules>
+	// </yandex>
+	util.Iline(b, 4, "</query_masking_rules>")
+	util.Iline(b, 0, "</"+xmlTagYandex+">")
+
+	return b.String()
+}
+
 // RemoteServersGeneratorOptions specifies options for remote-servers generator
 type RemoteServersGeneratorOptions struct {
 	exclude struct {
@@ -291,6 +342,11 @@ func (c *ClickHouseConfigGenerator) GetRemoteServers(options *RemoteServersGener
 		// <my_cluster_name>
 		util.Iline(b, 8, "<%s>", cluster.Name)
 
+		//		<secret>XXX</secret>
+		if secret := cluster.Secret.GetResolved(); secret != "" {
+			util.Iline(b, 12, "<secret>%s</secret>", escapeXML(secret))
+		}
+
 		// Build each shard XML
 		cluster.WalkShards(func(index int, shard *chiv1.ChiShard) error {
 			if c.ShardHostsNum(shard, options) < 1 {
@@ -313,10 +369,14 @@ func (c *ClickHouseConfigGenerator) GetRemoteServers(options *RemoteServersGener
 					// <replica>
 					//		<host>XXX</host>
 					//		<port>XXX</port>
+					//		<priority>X</priority>
 					// </replica>
 					util.Iline(b, 16, "<replica>")
 					util.Iline(b, 16, "    <host>%s</host>", c.getRemoteServersReplicaHostname(host))
 					util.Iline(b, 16, "    <port>%d</port>", host.TCPPort)
+					if host.Priority > 0 {
+						util.Iline(b, 16, "    <priority>%d</priority>", host.Priority)
+					}
 					util.Iline(b, 16, "</replica>")
 				}
 				return nil
@@ -415,7 +475,7 @@ func (c *ClickHouseConfigGenerator) GetHostMacros(host *chiv1.ChiHost) string {
 	util.Iline(b, 0, "    <macros>")
 
 	// <installation>CHI-name-macros-value</installation>
-	util.Iline(b, 8, "<installation>%s</installation>", host.Address.CHIName)
+	util.Iline(b, 8, "<installation>%s</installation>", getInstallationName(host.Address.CHIName, host.Address.Namespace))
 
 	// <CLUSTER_NAME>cluster-name-macros-value</CLUSTER_NAME>
 	// util.Iline(b, 8, "<%s>%[2]s</%[1]s>", replica.Address.ClusterName, c.getMacrosCluster(replica.Address.ClusterName))
@@ -434,6 +494,9 @@ func (c *ClickHouseConfigGenerator) GetHostMacros(host *chiv1.ChiHost) string {
 	// <replica>replica id = full deployment id</replica>
 	// full deployment id is unique to identify replica within the cluster
 	util.Iline(b, 8, "<replica>%s</replica>", CreatePodHostname(host))
+	// <replica_id>stable replica identity</replica_id>
+	// derived from replica address, suitable for auto-replicated paths, ex.: Replicated database engine
+	util.Iline(b, 8, "<replica_id>%s</replica_id>", CreateReplicaID(host))
 
 	// 		</macros>
 	// </yandex>
@@ -443,6 +506,15 @@ func (c *ClickHouseConfigGenerator) GetHostMacros(host *chiv1.ChiHost) string {
 	return b.String()
 }
 
+// getInstallationName returns name of the installation used in <installation> macro and Zookeeper paths,
+// optionally suffixed with namespace ID, so identically named CHIs in different namespaces do not collide
+func getInstallationName(name, namespace string) string {
+	if chop.Config().CHConfigMacroInstallationNamespaceSuffix {
+		return name + "-" + newNamer(namerContextNames).namePartNamespaceID(namespace)
+	}
+	return name
+}
+
 // noCustomPorts
 func noCustomPorts(host *chiv1.ChiHost) bool {
 	if host.TCPPort != chDefaultTCPPortNumber {
@@ -457,6 +529,14 @@ func noCustomPorts(host *chiv1.ChiHost) bool {
 		return false
 	}
 
+	if host.MySQLPort != chPortNumberMustBeAssignedLater {
+		return false
+	}
+
+	if host.PostgreSQLPort != chPortNumberMustBeAssignedLater {
+		return false
+	}
+
 	return true
 }
 
@@ -481,6 +561,12 @@ func (c *ClickHouseConfigGenerator) GetHostPorts(host *chiv1.ChiHost) string {
 	if host.InterserverHTTPPort != chDefaultInterserverHTTPPortNumber {
 		util.Iline(b, 4, "<interserver_http_port>%d</interserver_http_port>", host.InterserverHTTPPort)
 	}
+	if host.MySQLPort != chPortNumberMustBeAssignedLater {
+		util.Iline(b, 4, "<mysql_port>%d</mysql_port>", host.MySQLPort)
+	}
+	if host.PostgreSQLPort != chPortNumberMustBeAssignedLater {
+		util.Iline(b, 4, "<postgresql_port>%d</postgresql_port>", host.PostgreSQLPort)
+	}
 
 	// </yandex>
 	util.Iline(b, 0, "</"+xmlTagYandex+">")
@@ -509,9 +595,16 @@ func (c *ClickHouseConfigGenerator) generateXMLConfig(settings *chiv1.Settings,
 // Paths and Names section
 //
 
+// escapeXML escapes special XML characters of the value
+func escapeXML(value string) string {
+	b := &bytes.Buffer{}
+	_ = xml.EscapeText(b, []byte(value))
+	return b.String()
+}
+
 // getDistributedDDLPath returns string path used in <distributed_ddl><path>XXX</path></distributed_ddl>
 func (c *ClickHouseConfigGenerator) getDistributedDDLPath() string {
-	return fmt.Sprintf(distributedDDLPathPattern, c.chi.Name)
+	return fmt.Sprintf(distributedDDLPathPattern, getInstallationName(c.chi.Name, c.chi.Namespace))
 }
 
 // getRemoteServersReplicaHostname returns hostname (podhostname + service or FQDN) for "remote_servers.xml"
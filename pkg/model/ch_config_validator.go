@@ -0,0 +1,243 @@
+// Copyright 2019 Altinity Ltd and/or its affiliates. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	log "github.com/altinity/clickhouse-operator/pkg/announcer"
+	"github.com/altinity/clickhouse-operator/pkg/chop"
+)
+
+// configStructureValidators specifies structural validators of generated config files.
+// Files not listed here are checked to be well-formed only
+var configStructureValidators = map[string]func(root *xmlNode) error{
+	createConfigSectionFilename(configRemoteServers): validateRemoteServersStructure,
+	createConfigSectionFilename(configUsers):         validateUsersStructure,
+}
+
+// ValidateConfigFiles checks XML config files to be well-formed.
+// Files, which have structural rules, are checked to have required nesting as well.
+// Structural issues are errors only in case it is enabled in operator's config, warnings otherwise.
+// Returned error identifies the first malformed file. Non-XML files are skipped.
+func ValidateConfigFiles(files map[string]string) error {
+	// Walk over files in stable order, so the same file is reported each time
+	filenames := make([]string, 0, len(files))
+	for filename := range files {
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+
+	for _, filename := range filenames {
+		if !isXMLConfigFile(filename) {
+			continue
+		}
+		if err := validateXML(files[filename]); err != nil {
+			return fmt.Errorf("malformed XML in config file %s err: %v", filename, err)
+		}
+		if validator, ok := configStructureValidators[filename]; ok {
+			if err := validateXMLStructure(files[filename], validator); err != nil {
+				if chop.Config().CHConfigValidateStructure {
+					return fmt.Errorf("invalid structure of config file %s err: %v", filename, err)
+				}
+				log.V(1).F().Warning("invalid structure of config file %s err: %v", filename, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// isXMLConfigFile checks whether file is expected to contain XML
+func isXMLConfigFile(filename string) bool {
+	return strings.HasSuffix(strings.ToLower(filename), ".xml")
+}
+
+// validateXML checks XML to be well-formed and to have exactly one root element
+func validateXML(content string) error {
+	decoder := xml.NewDecoder(strings.NewReader(content))
+	depth := 0
+	roots := 0
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		switch token.(type) {
+		case xml.StartElement:
+			if depth == 0 {
+				roots++
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+		}
+	}
+
+	if roots != 1 {
+		return fmt.Errorf("expected exactly one root element, found %d", roots)
+	}
+
+	return nil
+}
+
+// xmlNode is a generic XML element, used for structural validation
+type xmlNode struct {
+	XMLName xml.Name
+	Content string     `xml:",chardata"`
+	Nodes   []*xmlNode `xml:",any"`
+}
+
+// name gets name of the element
+func (n *xmlNode) name() string {
+	return n.XMLName.Local
+}
+
+// children gets child elements with specified name
+func (n *xmlNode) children(name string) []*xmlNode {
+	var res []*xmlNode
+	for _, node := range n.Nodes {
+		if node.name() == name {
+			res = append(res, node)
+		}
+	}
+	return res
+}
+
+// has checks whether element has child element with specified name
+func (n *xmlNode) has(name string) bool {
+	return len(n.children(name)) > 0
+}
+
+// find gets descendant elements by slash-separated path of element names, ex.: "merge_tree/storage_policy"
+func (n *xmlNode) find(path string) []*xmlNode {
+	nodes := []*xmlNode{n}
+	for _, name := range strings.Split(path, "/") {
+		var next []*xmlNode
+		for _, node := range nodes {
+			next = append(next, node.children(name)...)
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+// validateXMLStructure parses well-formed XML and applies structural validator to it
+func validateXMLStructure(content string, validator func(root *xmlNode) error) error {
+	root := &xmlNode{}
+	if err := xml.Unmarshal([]byte(content), root); err != nil {
+		return err
+	}
+	return validator(root)
+}
+
+// ValidateStoragePolicy checks default storage policy of MergeTree tables, if specified in any of config files,
+// to be defined in storage_configuration of config files. Groups of config files are expected to be
+// all config files of a host, ex.: common and personal ones, so effective host config is checked.
+// Policies defined in config files baked into ClickHouse image are not known to the operator
+// and have to be provided via files or operator's config files as well.
+// Unknown default storage policy prevents MergeTree tables from being created.
+func ValidateStoragePolicy(groups ...map[string]string) error {
+	defined := make(map[string]bool)
+	var referenced []string
+	for _, files := range groups {
+		for filename, content := range files {
+			if !isXMLConfigFile(filename) {
+				continue
+			}
+			root := &xmlNode{}
+			if err := xml.Unmarshal([]byte(content), root); err != nil {
+				// Malformed files are reported by ValidateConfigFiles
+				continue
+			}
+			for _, policy := range root.find(configSettingMergeTreeStoragePolicy) {
+				referenced = append(referenced, strings.TrimSpace(policy.Content))
+			}
+			for _, policies := range root.find(configSettingStoragePolicies) {
+				for _, policy := range policies.Nodes {
+					defined[policy.name()] = true
+				}
+			}
+		}
+	}
+
+	// Report policies in stable order
+	sort.Strings(referenced)
+	for _, policy := range referenced {
+		if (policy == "") || (policy == storagePolicyDefault) || defined[policy] {
+			continue
+		}
+		return fmt.Errorf("default MergeTree storage policy %s is not defined in storage_configuration", policy)
+	}
+
+	return nil
+}
+
+// validateRemoteServersStructure checks remote_servers to have cluster->shard->replica nesting
+func validateRemoteServersStructure(root *xmlNode) error {
+	// <yandex>
+	//   <remote_servers>
+	//     <cluster>
+	//       <shard>
+	//         <replica>
+	//           <host>XXX</host>
+	//           <port>XXX</port>
+	for _, remoteServers := range root.children(configRemoteServers) {
+		for _, cluster := range remoteServers.Nodes {
+			shards := cluster.children("shard")
+			if len(shards) == 0 {
+				return fmt.Errorf("cluster %s has no shards", cluster.name())
+			}
+			for i, shard := range shards {
+				replicas := shard.children("replica")
+				if len(replicas) == 0 {
+					return fmt.Errorf("cluster %s shard %d has no replicas", cluster.name(), i)
+				}
+				for j, replica := range replicas {
+					for _, required := range []string{"host", "port"} {
+						if !replica.has(required) {
+							return fmt.Errorf("cluster %s shard %d replica %d has no %s", cluster.name(), i, j, required)
+						}
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateUsersStructure checks users to have user->networks nesting
+func validateUsersStructure(root *xmlNode) error {
+	// <yandex>
+	//   <users>
+	//     <user>
+	//       <networks>
+	for _, users := range root.children(configUsers) {
+		for _, user := range users.Nodes {
+			if !user.has("networks") {
+				return fmt.Errorf("user %s has no networks", user.name())
+			}
+		}
+	}
+	return nil
+}
@@ -16,13 +16,17 @@ package model
 
 import (
 	"fmt"
+	"strings"
 
 	// "net/url"
 
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	log "github.com/altinity/clickhouse-operator/pkg/announcer"
@@ -59,7 +63,7 @@ func (c *Creator) CreateServiceCHI() *corev1.Service {
 	c.a.V(1).F().Info("%s/%s", c.chi.Namespace, serviceName)
 	if template, ok := c.chi.GetCHIServiceTemplate(); ok {
 		// .templates.ServiceTemplate specified
-		return c.createServiceFromTemplate(
+		svc := c.createServiceFromTemplate(
 			template,
 			c.chi.Namespace,
 			serviceName,
@@ -69,6 +73,10 @@ func (c *Creator) CreateServiceCHI() *corev1.Service {
 			ownerReferences,
 			macro(c.chi),
 		)
+		if svc != nil {
+			setupServiceAllocateLoadBalancerNodePorts(svc)
+		}
+		return svc
 	}
 
 	// Create default Service
@@ -100,8 +108,16 @@ func (c *Creator) CreateServiceCHI() *corev1.Service {
 			Selector:              c.labels.getSelectorCHIScopeReady(),
 			Type:                  corev1.ServiceTypeLoadBalancer,
 			ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeLocal,
+			// Client-facing service should not expose not ready pods
+			PublishNotReadyAddresses: chop.Config().CHIServicePublishNotReadyAddresses,
 		},
 	}
+	// MySQL and PostgreSQL protocols are exposed in case they are enabled in common settings
+	settings := c.chi.Spec.Configuration.Settings
+	svc.Spec.Ports = appendServicePortByName(svc.Spec.Ports, chMySQLPortName, settings.GetMySQLPort(), intstr.FromString(chMySQLPortName))
+	svc.Spec.Ports = appendServicePortByName(svc.Spec.Ports, chPostgreSQLPortName, settings.GetPostgreSQLPort(), intstr.FromString(chPostgreSQLPortName))
+	setupServiceIPFamilies(svc)
+	setupServiceAllocateLoadBalancerNodePorts(svc)
 	MakeObjectVersionLabel(&svc.ObjectMeta, svc)
 	return svc
 }
@@ -125,8 +141,60 @@ func (c *Creator) CreateServiceCluster(cluster *chiv1.ChiCluster) *corev1.Servic
 			macro(cluster),
 		)
 	}
-	// No template specified, no need to create service
-	return nil
+
+	if !chop.Config().ClusterService {
+		// Default cluster Service is not requested
+		return nil
+	}
+
+	// Create default Service
+	// We do not have .templates.ServiceTemplate specified or it is incorrect
+	// Default cluster Service is internal one, load-balancing across all replicas of the cluster.
+	// Ports are taken from the first host, as all hosts of the cluster are expected to listen on the same ports
+	host := cluster.FirstHost()
+	if host == nil {
+		return nil
+	}
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            serviceName,
+			Namespace:       cluster.Address.Namespace,
+			Labels:          macro(cluster).Map(c.labels.getServiceCluster(cluster)),
+			Annotations:     macro(cluster).Map(c.annotations.getServiceCluster(cluster)),
+			OwnerReferences: ownerReferences,
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{
+					Name:       chDefaultHTTPPortName,
+					Protocol:   corev1.ProtocolTCP,
+					Port:       host.HTTPPort,
+					TargetPort: intstr.FromString(chDefaultHTTPPortName),
+				},
+				{
+					Name:       chDefaultTCPPortName,
+					Protocol:   corev1.ProtocolTCP,
+					Port:       host.TCPPort,
+					TargetPort: intstr.FromString(chDefaultTCPPortName),
+				},
+			},
+			Selector: getSelectorClusterScopeReady(cluster),
+			Type:     corev1.ServiceTypeClusterIP,
+			// Client-facing service should not expose not ready pods
+			PublishNotReadyAddresses: chop.Config().CHIServicePublishNotReadyAddresses,
+		},
+	}
+	setupServiceIPFamilies(svc)
+	MakeObjectVersionLabel(&svc.ObjectMeta, svc)
+	return svc
+}
+
+// hasServiceCluster checks whether Service is to be created for specified Cluster
+func hasServiceCluster(cluster *chiv1.ChiCluster) bool {
+	if _, ok := cluster.GetServiceTemplate(); ok {
+		return true
+	}
+	return chop.Config().ClusterService
 }
 
 // CreateServiceShard creates new corev1.Service for specified Shard
@@ -204,12 +272,58 @@ func (c *Creator) CreateServiceHost(host *chiv1.ChiHost) *corev1.Service {
 					TargetPort: intstr.FromInt(int(host.InterserverHTTPPort)),
 				},
 			},
-			Selector:                 GetSelectorHostScope(host),
-			ClusterIP:                templateDefaultsServiceClusterIP,
-			Type:                     "ClusterIP",
-			PublishNotReadyAddresses: true,
+			Selector:  GetSelectorHostScope(host),
+			ClusterIP: templateDefaultsServiceClusterIP,
+			Type:      "ClusterIP",
+			// Headless governing service publishes not ready pods, so replicas can discover each other during startup
+			PublishNotReadyAddresses: chop.Config().HostServicePublishNotReadyAddresses,
 		},
 	}
+	svc.Spec.Ports = appendServicePortByName(svc.Spec.Ports, chMySQLPortName, host.MySQLPort, intstr.FromInt(int(host.MySQLPort)))
+	svc.Spec.Ports = appendServicePortByName(svc.Spec.Ports, chPostgreSQLPortName, host.PostgreSQLPort, intstr.FromInt(int(host.PostgreSQLPort)))
+	setupServiceIPFamilies(svc)
+	MakeObjectVersionLabel(&svc.ObjectMeta, svc)
+	return svc
+}
+
+// CreateServiceHostMetrics creates new metrics-only corev1.Service for specified host.
+// Returns nil in case metrics-only Services are disabled or host has no prometheus endpoint configured
+func (c *Creator) CreateServiceHostMetrics(host *chiv1.ChiHost) *corev1.Service {
+	if !chop.Config().HostMetricsService {
+		return nil
+	}
+
+	port := getHostPrometheusPort(host)
+	if port <= 0 {
+		return nil
+	}
+
+	serviceName := CreateHostMetricsServiceName(host)
+	ownerReferences := getOwnerReferences(c.chi.TypeMeta, c.chi.ObjectMeta, true, true)
+
+	c.a.V(1).F().Info("%s/%s", host.Address.Namespace, serviceName)
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            serviceName,
+			Namespace:       host.Address.Namespace,
+			Labels:          macro(host).Map(c.labels.getServiceHostMetrics(host)),
+			Annotations:     macro(host).Map(c.annotations.getServiceHost(host)),
+			OwnerReferences: ownerReferences,
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{
+					Name:       chPrometheusPortName,
+					Protocol:   corev1.ProtocolTCP,
+					Port:       port,
+					TargetPort: intstr.FromInt(int(port)),
+				},
+			},
+			Selector: GetSelectorHostScope(host),
+			Type:     corev1.ServiceTypeClusterIP,
+		},
+	}
+	setupServiceIPFamilies(svc)
 	MakeObjectVersionLabel(&svc.ObjectMeta, svc)
 	return svc
 }
@@ -262,12 +376,51 @@ func (c *Creator) createServiceFromTemplate(
 	// Append provided Selector to already specified Selector in template
 	service.Spec.Selector = util.MergeStringMapsOverwrite(service.Spec.Selector, selector)
 
+	// IP families specified in template have priority over operator's defaults
+	setupServiceIPFamilies(service)
+
 	// And after the object is ready we can put version label
 	MakeObjectVersionLabel(&service.ObjectMeta, service)
 
 	return service
 }
 
+// appendServicePortByName appends TCP port to service ports, in case port is specified
+func appendServicePortByName(ports []corev1.ServicePort, name string, port int32, targetPort intstr.IntOrString) []corev1.ServicePort {
+	if port == chPortNumberMustBeAssignedLater {
+		// Port is not specified, protocol is not enabled
+		return ports
+	}
+	return append(ports, corev1.ServicePort{
+		Name:       name,
+		Protocol:   corev1.ProtocolTCP,
+		Port:       port,
+		TargetPort: targetPort,
+	})
+}
+
+// setupServiceIPFamilies sets IP family policy and IP families from operator's config,
+// in case they are not specified explicitly. Unspecified in config means cluster default
+func setupServiceIPFamilies(service *corev1.Service) {
+	if service.Spec.IPFamilyPolicy == nil {
+		service.Spec.IPFamilyPolicy = chop.Config().GetServiceIPFamilyPolicy()
+	}
+	if len(service.Spec.IPFamilies) == 0 {
+		service.Spec.IPFamilies = chop.Config().GetServiceIPFamilies()
+	}
+}
+
+// setupServiceAllocateLoadBalancerNodePorts sets whether to allocate NodePorts for LoadBalancer Service from operator's config,
+// in case it is not specified explicitly. Unspecified in config means cluster default
+func setupServiceAllocateLoadBalancerNodePorts(service *corev1.Service) {
+	if service.Spec.Type != corev1.ServiceTypeLoadBalancer {
+		return
+	}
+	if service.Spec.AllocateLoadBalancerNodePorts == nil {
+		service.Spec.AllocateLoadBalancerNodePorts = chop.Config().GetCHIServiceAllocateLoadBalancerNodePorts()
+	}
+}
+
 // CreateConfigMapCHICommon creates new corev1.ConfigMap
 func (c *Creator) CreateConfigMapCHICommon(options *ClickHouseConfigFilesGeneratorOptions) *corev1.ConfigMap {
 	cm := &corev1.ConfigMap{
@@ -327,7 +480,7 @@ func (c *Creator) CreateStatefulSet(host *chiv1.ChiHost, shutdown bool) *apps.St
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            CreateStatefulSetName(host),
 			Namespace:       host.Address.Namespace,
-			Labels:          macro(host).Map(c.labels.getHostScope(host, true)),
+			Labels:          macro(host).Map(c.labels.getStatefulSet(host)),
 			Annotations:     macro(host).Map(c.annotations.getHostScope(host)),
 			OwnerReferences: getOwnerReferences(c.chi.TypeMeta, c.chi.ObjectMeta, true, true),
 		},
@@ -354,6 +507,7 @@ func (c *Creator) CreateStatefulSet(host *chiv1.ChiHost, shutdown bool) *apps.St
 		},
 	}
 
+	c.setupStatefulSetUpdateStrategy(statefulSet, host)
 	c.setupStatefulSetPodTemplate(statefulSet, host)
 	c.setupStatefulSetVolumeClaimTemplates(statefulSet, host)
 	c.setupStatefulSetVersion(statefulSet)
@@ -364,6 +518,19 @@ func (c *Creator) CreateStatefulSet(host *chiv1.ChiHost, shutdown bool) *apps.St
 	return statefulSet
 }
 
+// setupStatefulSetUpdateStrategy applies rolling update partition specified for the host.
+// Host's StatefulSet has exactly one pod, so partition either holds the pod on its current revision or not.
+func (c *Creator) setupStatefulSetUpdateStrategy(statefulSet *apps.StatefulSet, host *chiv1.ChiHost) {
+	if host.Partition <= 0 {
+		return
+	}
+	// Values above 1 have the same effect as 1
+	partition := int32(1)
+	statefulSet.Spec.UpdateStrategy.RollingUpdate = &apps.RollingUpdateStatefulSetStrategy{
+		Partition: &partition,
+	}
+}
+
 // setupStatefulSetVersion
 // TODO property of the labeler?
 func (c *Creator) setupStatefulSetVersion(statefulSet *apps.StatefulSet) {
@@ -418,14 +585,21 @@ func (c *Creator) setupStatefulSetPodTemplate(statefulSet *apps.StatefulSet, hos
 	c.statefulSetApplyPodTemplate(statefulSet, podTemplate, host)
 
 	// Post-process StatefulSet
-	ensureStatefulSetTemplateIntegrity(statefulSet, host)
+	ensureStatefulSetTemplateIntegrity(statefulSet, podTemplate, host)
+	setupWarmup(statefulSet, podTemplate, host)
+	setupZookeeperStartupProbe(statefulSet, podTemplate, host)
+	setupGracefulShutdown(statefulSet, podTemplate, host)
+	setupDebugSidecar(statefulSet, podTemplate)
 	c.personalizeStatefulSetTemplate(statefulSet, host)
+	// Guaranteed QoS has to be applied after all containers are added to the pod
+	c.setupGuaranteedQoS(statefulSet, podTemplate)
 }
 
 // ensureStatefulSetTemplateIntegrity
-func ensureStatefulSetTemplateIntegrity(statefulSet *apps.StatefulSet, host *chiv1.ChiHost) {
+func ensureStatefulSetTemplateIntegrity(statefulSet *apps.StatefulSet, template *chiv1.ChiPodTemplate, host *chiv1.ChiHost) {
 	ensureClickHouseContainerSpecified(statefulSet)
-	ensureProbesSpecified(statefulSet)
+	ensureClickHouseContainerPoliciesSpecified(statefulSet)
+	ensureProbesSpecified(statefulSet, template, host)
 	ensureNamedPortsSpecified(statefulSet, host)
 }
 
@@ -443,8 +617,22 @@ func ensureClickHouseContainerSpecified(statefulSet *apps.StatefulSet) {
 	)
 }
 
+// ensureClickHouseContainerPoliciesSpecified
+func ensureClickHouseContainerPoliciesSpecified(statefulSet *apps.StatefulSet) {
+	container, ok := getClickHouseContainer(statefulSet)
+	if !ok {
+		return
+	}
+	if container.TerminationMessagePolicy == "" {
+		container.TerminationMessagePolicy = chop.Config().GetTerminationMessagePolicy()
+	}
+	if container.ImagePullPolicy == "" {
+		container.ImagePullPolicy = chop.Config().GetImagePullPolicy()
+	}
+}
+
 // ensureProbesSpecified
-func ensureProbesSpecified(statefulSet *apps.StatefulSet) {
+func ensureProbesSpecified(statefulSet *apps.StatefulSet, template *chiv1.ChiPodTemplate, host *chiv1.ChiHost) {
 	container, ok := getClickHouseContainer(statefulSet)
 	if !ok {
 		return
@@ -453,8 +641,187 @@ func ensureProbesSpecified(statefulSet *apps.StatefulSet) {
 		container.LivenessProbe = newDefaultLivenessProbe()
 	}
 	if container.ReadinessProbe == nil {
-		container.ReadinessProbe = newDefaultReadinessProbe()
+		if template.IsAttachedReadinessProbe() {
+			container.ReadinessProbe = newAttachedReadinessProbe(host.TCPPort)
+		} else {
+			container.ReadinessProbe = newDefaultReadinessProbe()
+		}
+	}
+}
+
+// setupWarmup sets up postStart hook, which runs cache-priming queries against local ClickHouse after start
+func setupWarmup(statefulSet *apps.StatefulSet, template *chiv1.ChiPodTemplate, host *chiv1.ChiHost) {
+	if !template.Warmup.HasQueries() {
+		// No warmup requested
+		return
+	}
+
+	container, ok := getClickHouseContainer(statefulSet)
+	if !ok {
+		// Unable to locate ClickHouse container
+		return
+	}
+
+	if (container.Lifecycle != nil) && (container.Lifecycle.PostStart != nil) {
+		// User-specified postStart hook has priority
+		return
 	}
+
+	if container.Lifecycle == nil {
+		container.Lifecycle = &corev1.Lifecycle{}
+	}
+	container.Lifecycle.PostStart = newWarmupHandler(
+		template.Warmup.Queries,
+		host.TCPPort,
+		template.Warmup.GetTimeoutSeconds(warmupQueriesTimeoutSeconds),
+	)
+}
+
+// setupGracefulShutdown sets up preStop hook, which stops merges and flushes logs before ClickHouse is terminated,
+// so no half-merged parts are left behind. Shutdown itself is performed by SIGTERM sent after the hook completes.
+func setupGracefulShutdown(statefulSet *apps.StatefulSet, template *chiv1.ChiPodTemplate, host *chiv1.ChiHost) {
+	if !template.IsGracefulShutdown() {
+		// No graceful shutdown requested
+		return
+	}
+
+	container, ok := getClickHouseContainer(statefulSet)
+	if !ok {
+		// Unable to locate ClickHouse container
+		return
+	}
+
+	if (container.Lifecycle != nil) && (container.Lifecycle.PreStop != nil) {
+		// User-specified preStop hook has priority
+		return
+	}
+
+	// preStop hook is accounted in termination grace period, so leave half of it to ClickHouse shutdown itself
+	timeout := int64(gracefulShutdownMinSeconds)
+	if gracePeriod := statefulSet.Spec.Template.Spec.TerminationGracePeriodSeconds; (gracePeriod != nil) && (*gracePeriod/2 > timeout) {
+		timeout = *gracePeriod / 2
+	}
+
+	if container.Lifecycle == nil {
+		container.Lifecycle = &corev1.Lifecycle{}
+	}
+	container.Lifecycle.PreStop = newGracefulShutdownHandler(host.TCPPort, timeout)
+}
+
+// setupGuaranteedQoS copies resource requests into limits of all containers, so Pod gets Guaranteed QoS class
+func (c *Creator) setupGuaranteedQoS(statefulSet *apps.StatefulSet, template *chiv1.ChiPodTemplate) {
+	if !template.IsGuaranteedQoS() {
+		// No Guaranteed QoS requested
+		return
+	}
+
+	podSpec := &statefulSet.Spec.Template.Spec
+	for i := range podSpec.InitContainers {
+		setupContainerLimitsEqualToRequests(&podSpec.InitContainers[i])
+	}
+	for i := range podSpec.Containers {
+		container := &podSpec.Containers[i]
+		if (container.Name == ClickHouseLogContainerName) && isContainerResourcesEmpty(container) {
+			// Log container is added by the operator and has no resources specified,
+			// which would make the pod Burstable, so provide minimal resources for it
+			container.Resources = newSidecarResources()
+		}
+		setupContainerLimitsEqualToRequests(container)
+		if !isContainerResourcesGuaranteed(container) {
+			c.a.V(1).F().Warning(
+				"statefulSet %s container %s has no CPU or memory requests, pod would not get Guaranteed QoS class",
+				statefulSet.Name,
+				container.Name,
+			)
+		}
+	}
+}
+
+// isContainerResourcesGuaranteed checks whether container has both CPU and memory limits specified,
+// which is required for Guaranteed QoS class
+func isContainerResourcesGuaranteed(container *corev1.Container) bool {
+	_, cpu := container.Resources.Limits[corev1.ResourceCPU]
+	_, memory := container.Resources.Limits[corev1.ResourceMemory]
+	return cpu && memory
+}
+
+// isContainerResourcesEmpty checks whether container has neither requests nor limits specified
+func isContainerResourcesEmpty(container *corev1.Container) bool {
+	return (len(container.Resources.Requests) == 0) && (len(container.Resources.Limits) == 0)
+}
+
+// newSidecarResources returns minimal resources for sidecar containers added by the operator, with limits equal to requests
+func newSidecarResources() corev1.ResourceRequirements {
+	resources := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse(sidecarCPU),
+		corev1.ResourceMemory: resource.MustParse(sidecarMemory),
+	}
+	return corev1.ResourceRequirements{
+		Requests: resources,
+		Limits:   resources.DeepCopy(),
+	}
+}
+
+// setupContainerLimitsEqualToRequests sets CPU and memory limits of the container equal to its requests
+func setupContainerLimitsEqualToRequests(container *corev1.Container) {
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		request, ok := container.Resources.Requests[name]
+		if !ok {
+			// Nothing to copy, limit (if any) is used as request by k8s
+			continue
+		}
+		if container.Resources.Limits == nil {
+			container.Resources.Limits = corev1.ResourceList{}
+		}
+		container.Resources.Limits[name] = request.DeepCopy()
+	}
+}
+
+// setupZookeeperStartupProbe sets up startup probe, which waits for Zookeeper connectivity, thus gating liveness probe
+func setupZookeeperStartupProbe(statefulSet *apps.StatefulSet, template *chiv1.ChiPodTemplate, host *chiv1.ChiHost) {
+	if !template.IsZookeeperStartupProbe() {
+		// No Zookeeper-aware startup probe requested
+		return
+	}
+
+	if host.GetZookeeper().IsEmpty() {
+		// No Zookeeper configured, nothing to wait for
+		return
+	}
+
+	container, ok := getClickHouseContainer(statefulSet)
+	if !ok {
+		// Unable to locate ClickHouse container
+		return
+	}
+
+	if container.StartupProbe != nil {
+		// User-specified startup probe has priority
+		return
+	}
+
+	container.StartupProbe = newZookeeperStartupProbe(host.TCPPort)
+}
+
+// setupDebugSidecar adds troubleshooting sidecar with clickhouse-client available.
+// Sidecar uses lightweight client-only image specified in operator's config, pulled with operator's imagePullPolicy.
+// Sidecar shares network namespace with ClickHouse container, so local ClickHouse is reachable via localhost.
+// No volumes are mounted into sidecar.
+func setupDebugSidecar(statefulSet *apps.StatefulSet, template *chiv1.ChiPodTemplate) {
+	if !template.IsDebugSidecar() {
+		// No debug sidecar requested
+		return
+	}
+
+	podSpec := &statefulSet.Spec.Template.Spec
+	for i := range podSpec.Containers {
+		if podSpec.Containers[i].Name == ClickHouseDebugContainerName {
+			// User-specified debug container has priority
+			return
+		}
+	}
+
+	addContainer(podSpec, newDebugContainer(chop.Config().DebugSidecarImage, chop.Config().GetImagePullPolicy()))
 }
 
 // personalizeStatefulSetTemplate
@@ -507,6 +874,7 @@ func (c *Creator) setupTroubleshoot(statefulSet *apps.StatefulSet) {
 	// Sleep is not able to respond to probes
 	container.LivenessProbe = nil
 	container.ReadinessProbe = nil
+	container.StartupProbe = nil
 }
 
 // setupLogContainer
@@ -563,6 +931,10 @@ func (c *Creator) setupConfigMapVolumes(statefulSetObject *apps.StatefulSet, hos
 	for i := range statefulSetObject.Spec.Template.Spec.Containers {
 		// Convenience wrapper
 		container := &statefulSetObject.Spec.Template.Spec.Containers[i]
+		if container.Name == ClickHouseDebugContainerName {
+			// Debug sidecar should not have access to configs, users.d includes credentials
+			continue
+		}
 		// Append to each Container current VolumeMount's to VolumeMount's declared in template
 		container.VolumeMounts = append(
 			container.VolumeMounts,
@@ -599,11 +971,28 @@ func (c *Creator) setupStatefulSetApplyVolumeClaimTemplates(statefulSet *apps.St
 	for i := range statefulSet.Spec.Template.Spec.Containers {
 		// Convenience wrapper
 		container := &statefulSet.Spec.Template.Spec.Containers[i]
-		_ = c.setupStatefulSetApplyVolumeMount(host, statefulSet, container.Name, newVolumeMount(host.Templates.GetDataVolumeClaimTemplate(), dirPathClickHouseData))
+		if container.Name == ClickHouseDebugContainerName {
+			// Debug sidecar should not have access to data
+			continue
+		}
+		_ = c.setupStatefulSetApplyVolumeMount(host, statefulSet, container.Name, newVolumeMount(host.Templates.GetDataVolumeClaimTemplate(), getHostDataPath(host)))
 		_ = c.setupStatefulSetApplyVolumeMount(host, statefulSet, container.Name, newVolumeMount(host.Templates.GetLogVolumeClaimTemplate(), dirPathClickHouseLog))
 	}
 }
 
+// getHostDataPath gets path of data folder of the host, so data volume is mounted where ClickHouse places its data.
+// Explicitly specified `path` setting has priority over default data folder
+func getHostDataPath(host *chiv1.ChiHost) string {
+	for _, settings := range []*chiv1.Settings{host.Settings, host.GetCHI().Spec.Configuration.Settings} {
+		if settings.Has(configSettingPath) {
+			if path := strings.TrimSuffix(settings.Get(configSettingPath).String(), "/"); path != "" {
+				return path
+			}
+		}
+	}
+	return dirPathClickHouseData
+}
+
 // setupStatefulSetVolumeClaimTemplates performs VolumeClaimTemplate setup for Containers in PodTemplate of a StatefulSet
 func (c *Creator) setupStatefulSetVolumeClaimTemplates(statefulSet *apps.StatefulSet, host *chiv1.ChiHost) {
 	c.setupStatefulSetApplyVolumeMounts(statefulSet, host)
@@ -636,6 +1025,9 @@ func (c *Creator) statefulSetApplyPodTemplate(
 	if statefulSet.Spec.Template.Spec.TerminationGracePeriodSeconds == nil {
 		statefulSet.Spec.Template.Spec.TerminationGracePeriodSeconds = chop.Config().GetTerminationGracePeriod()
 	}
+	if statefulSet.Spec.Template.Spec.EnableServiceLinks == nil {
+		statefulSet.Spec.Template.Spec.EnableServiceLinks = chop.Config().GetPodEnableServiceLinks()
+	}
 }
 
 // getClickHouseContainer
@@ -680,6 +1072,17 @@ func IsStatefulSetGeneration(statefulSet *apps.StatefulSet, generation int64) bo
 		return false
 	}
 
+	if partition := getStatefulSetPartition(statefulSet); partition > 0 {
+		// Held pod is not updated, so rolling update is never completed over all replicas
+		updated := *statefulSet.Spec.Replicas - partition
+		if updated < 0 {
+			updated = 0
+		}
+		return (statefulSet.Generation == generation) &&
+			(statefulSet.Status.ObservedGeneration == statefulSet.Generation) &&
+			(statefulSet.Status.UpdatedReplicas >= updated)
+	}
+
 	// StatefulSet has .spec generation we are looking for
 	return (statefulSet.Generation == generation) &&
 		// and this .spec generation is being applied to replicas - it is observed right now
@@ -692,6 +1095,17 @@ func IsStatefulSetGeneration(statefulSet *apps.StatefulSet, generation int64) bo
 		(statefulSet.Status.CurrentRevision == statefulSet.Status.UpdateRevision)
 }
 
+// getStatefulSetPartition returns rolling update partition of the StatefulSet, 0 in case none specified
+func getStatefulSetPartition(statefulSet *apps.StatefulSet) int32 {
+	if statefulSet.Spec.UpdateStrategy.RollingUpdate == nil {
+		return 0
+	}
+	if statefulSet.Spec.UpdateStrategy.RollingUpdate.Partition == nil {
+		return 0
+	}
+	return *statefulSet.Spec.UpdateStrategy.RollingUpdate.Partition
+}
+
 // IsStatefulSetReady returns whether StatefulSet is ready
 func IsStatefulSetReady(statefulSet *apps.StatefulSet) bool {
 	if statefulSet == nil {
@@ -738,6 +1152,26 @@ func ensureNamedPortsSpecified(statefulSet *apps.StatefulSet, host *chiv1.ChiHos
 	ensurePortByName(container, chDefaultTCPPortName, host.TCPPort)
 	ensurePortByName(container, chDefaultHTTPPortName, host.HTTPPort)
 	ensurePortByName(container, chDefaultInterserverHTTPPortName, host.InterserverHTTPPort)
+	if host.MySQLPort != chPortNumberMustBeAssignedLater {
+		ensurePortByName(container, chMySQLPortName, host.MySQLPort)
+	}
+	if host.PostgreSQLPort != chPortNumberMustBeAssignedLater {
+		ensurePortByName(container, chPostgreSQLPortName, host.PostgreSQLPort)
+	}
+	if chop.Config().CHIPodMonitor {
+		// PodMonitor refers metrics endpoint by port name
+		if port := getHostPrometheusPort(host); port > 0 {
+			ensurePortByName(container, chPrometheusPortName, port)
+		}
+	}
+}
+
+// getHostPrometheusPort gets Prometheus endpoint port of the host, 0 in case prometheus endpoint is not configured
+func getHostPrometheusPort(host *chiv1.ChiHost) int32 {
+	if port := host.Settings.GetPrometheusPort(); port > 0 {
+		return port
+	}
+	return host.GetCHI().Spec.Configuration.Settings.GetPrometheusPort()
 }
 
 // ensurePortByName
@@ -782,6 +1216,68 @@ func (c *Creator) NewPodDisruptionBudget() *v1beta1.PodDisruptionBudget {
 	}
 }
 
+const (
+	// podMonitorKind specifies kind of Prometheus Operator PodMonitor object
+	podMonitorKind = "PodMonitor"
+	// podMonitorDefaultMetricsPath specifies default HTTP path of ClickHouse prometheus endpoint
+	podMonitorDefaultMetricsPath = "/metrics"
+)
+
+// PodMonitorGVR specifies group, version and resource of Prometheus Operator PodMonitor objects
+var PodMonitorGVR = schema.GroupVersionResource{
+	Group:    "monitoring.coreos.com",
+	Version:  "v1",
+	Resource: "podmonitors",
+}
+
+// CreatePodMonitor creates new PodMonitor of Prometheus Operator selecting all pods of the CHI.
+// PodMonitor is created as unstructured object in order not to depend on Prometheus Operator API types.
+// Returns nil in case PodMonitor is disabled or CHI has no prometheus endpoint configured
+func (c *Creator) CreatePodMonitor() *unstructured.Unstructured {
+	if !chop.Config().CHIPodMonitor {
+		return nil
+	}
+
+	settings := c.chi.Spec.Configuration.Settings
+	if settings.GetPrometheusPort() <= 0 {
+		return nil
+	}
+
+	path := podMonitorDefaultMetricsPath
+	if settings.Has(configSettingPrometheusEndpoint) {
+		if endpoint := settings.Get(configSettingPrometheusEndpoint).String(); endpoint != "" {
+			path = endpoint
+		}
+	}
+
+	podMonitor := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"selector": map[string]interface{}{
+					"matchLabels": util.StringMapToInterfaceMap(c.labels.GetSelectorCHIScope()),
+				},
+				"namespaceSelector": map[string]interface{}{
+					"matchNames": []interface{}{c.chi.Namespace},
+				},
+				"podMetricsEndpoints": []interface{}{
+					map[string]interface{}{
+						"port": chPrometheusPortName,
+						"path": path,
+					},
+				},
+			},
+		},
+	}
+	podMonitor.SetGroupVersionKind(PodMonitorGVR.GroupVersion().WithKind(podMonitorKind))
+	podMonitor.SetName(CreatePodMonitorName(c.chi))
+	podMonitor.SetNamespace(c.chi.Namespace)
+	podMonitor.SetLabels(macro(c.chi).Map(c.labels.getCHIScope()))
+	podMonitor.SetAnnotations(macro(c.chi).Map(c.annotations.getCHIScope()))
+	podMonitor.SetOwnerReferences(getOwnerReferences(c.chi.TypeMeta, c.chi.ObjectMeta, true, true))
+
+	return podMonitor
+}
+
 // setupStatefulSetApplyVolumeMount applies .templates.volumeClaimTemplates.* to a StatefulSet
 func (c *Creator) setupStatefulSetApplyVolumeMount(
 	host *chiv1.ChiHost,
@@ -925,10 +1421,66 @@ func (c *Creator) statefulSetAppendPVCTemplate(
 	volumeMode := corev1.PersistentVolumeFilesystem
 	persistentVolumeClaim.Spec.VolumeMode = &volumeMode
 
+	// Data volume storage request may be overridden on per-host basis
+	if volumeClaimTemplate.Name == host.Templates.GetDataVolumeClaimTemplate() {
+		c.statefulSetApplyDataVolumeStorage(host, &persistentVolumeClaim)
+	}
+
 	// Append copy of PersistentVolumeClaimSpec
 	statefulSet.Spec.VolumeClaimTemplates = append(statefulSet.Spec.VolumeClaimTemplates, persistentVolumeClaim)
 }
 
+// statefulSetApplyDataVolumeStorage overrides storage request of the data volume claim with host's one, if any
+func (c *Creator) statefulSetApplyDataVolumeStorage(host *chiv1.ChiHost, persistentVolumeClaim *corev1.PersistentVolumeClaim) {
+	storage, ok := c.getDataVolumeStorage(host)
+	if !ok {
+		return
+	}
+
+	if persistentVolumeClaim.Spec.Resources.Requests == nil {
+		persistentVolumeClaim.Spec.Resources.Requests = corev1.ResourceList{}
+	}
+	persistentVolumeClaim.Spec.Resources.Requests[corev1.ResourceStorage] = storage
+}
+
+// GetPVCResourcesRequests gets desired resources requests of host's PVC, made out of specified volume claim template.
+// Storage request of the data volume claim may be overridden on per-host basis
+func (c *Creator) GetPVCResourcesRequests(host *chiv1.ChiHost, template *chiv1.ChiVolumeClaimTemplate) corev1.ResourceList {
+	requests := template.Spec.Resources.Requests
+	if template.Name != host.Templates.GetDataVolumeClaimTemplate() {
+		// Override is applicable to data volume claim only
+		return requests
+	}
+
+	storage, ok := c.getDataVolumeStorage(host)
+	if !ok {
+		return requests
+	}
+
+	requests = requests.DeepCopy()
+	if requests == nil {
+		requests = corev1.ResourceList{}
+	}
+	requests[corev1.ResourceStorage] = storage
+	return requests
+}
+
+// getDataVolumeStorage gets host's override of data volume storage request, if any
+func (c *Creator) getDataVolumeStorage(host *chiv1.ChiHost) (resource.Quantity, bool) {
+	if host.DataVolumeStorage == "" {
+		// No override specified
+		return resource.Quantity{}, false
+	}
+
+	storage, err := resource.ParseQuantity(host.DataVolumeStorage)
+	if err != nil {
+		c.a.V(1).F().Warning("Unable to parse dataVolumeStorage %s for host %s err: %v", host.DataVolumeStorage, host.Name, err)
+		return resource.Quantity{}, false
+	}
+
+	return storage, true
+}
+
 // newDefaultHostTemplate returns default Host Template to be used with StatefulSet
 func newDefaultHostTemplate(name string) *chiv1.ChiHostTemplate {
 	return &chiv1.ChiHostTemplate{
@@ -1014,6 +1566,86 @@ func newDefaultReadinessProbe() *corev1.Probe {
 	}
 }
 
+// newAttachedReadinessProbe returns readiness probe, which succeeds only after all databases are attached
+// and replicated tables are writable. ClickHouse accepts queries only after databases are attached at startup,
+// and replicated tables stay read-only until they are activated in ZooKeeper, so throwIf fails the query meanwhile.
+func newAttachedReadinessProbe(port int32) *corev1.Probe {
+	return &corev1.Probe{
+		Handler: corev1.Handler{
+			Exec: &corev1.ExecAction{
+				Command: []string{
+					"clickhouse-client",
+					fmt.Sprintf("--port=%d", port),
+					"--query=SELECT throwIf(count() > 0) FROM system.replicas WHERE is_readonly FORMAT Null",
+				},
+			},
+		},
+		InitialDelaySeconds: 10,
+		PeriodSeconds:       10,
+		TimeoutSeconds:      attachedReadinessProbeTimeoutSeconds,
+	}
+}
+
+// newWarmupHandler returns postStart handler, which waits for ClickHouse to accept connections
+// and runs provided queries one by one. Queries are passed as positional arguments in order to avoid quoting issues.
+// Queries are bounded by timeout, because container is not Running and probes do not start until postStart completes.
+// Handler never fails, because failed postStart hook kills the container.
+func newWarmupHandler(queries []string, port int32, timeout int) *corev1.Handler {
+	client := fmt.Sprintf("clickhouse-client --port=%d", port)
+	script := fmt.Sprintf(
+		"for i in $(seq 1 %d); do %s --query='SELECT 1' >/dev/null 2>&1 && break; sleep 1; done; "+
+			"timeout %d sh -c 'for query in \"$@\"; do %s --query=\"$query\" >/dev/null 2>&1; done' warmup \"$@\"; "+
+			"exit 0",
+		warmupWaitSeconds,
+		client,
+		timeout,
+		client,
+	)
+	command := append([]string{"/bin/sh", "-c", script, "warmup"}, queries...)
+	return &corev1.Handler{
+		Exec: &corev1.ExecAction{
+			Command: command,
+		},
+	}
+}
+
+// newGracefulShutdownHandler returns preStop handler, which stops merges and flushes logs of the local ClickHouse.
+// Whole sequence is bounded by timeout. Handler never fails, so termination proceeds in any case.
+func newGracefulShutdownHandler(port int32, timeout int64) *corev1.Handler {
+	client := fmt.Sprintf("clickhouse-client --port=%d", port)
+	script := fmt.Sprintf(
+		"timeout %d sh -c \"%s --query='SYSTEM STOP MERGES'; %s --query='SYSTEM FLUSH LOGS'\" >/dev/null 2>&1; "+
+			"exit 0",
+		timeout,
+		client,
+		client,
+	)
+	return &corev1.Handler{
+		Exec: &corev1.ExecAction{
+			Command: []string{"/bin/sh", "-c", script},
+		},
+	}
+}
+
+// newZookeeperStartupProbe returns startup probe, which succeeds as soon as ClickHouse is able to query Zookeeper
+func newZookeeperStartupProbe(port int32) *corev1.Probe {
+	return &corev1.Probe{
+		Handler: corev1.Handler{
+			Exec: &corev1.ExecAction{
+				Command: []string{
+					"clickhouse-client",
+					fmt.Sprintf("--port=%d", port),
+					"--query=SELECT count() FROM system.zookeeper WHERE path = '/'",
+				},
+			},
+		},
+		InitialDelaySeconds: 10,
+		PeriodSeconds:       10,
+		TimeoutSeconds:      10,
+		FailureThreshold:    zookeeperStartupProbeFailureThreshold,
+	}
+}
+
 // newDefaultClickHouseContainer returns default ClickHouse Container
 func newDefaultClickHouseContainer() corev1.Container {
 	return corev1.Container{
@@ -1052,6 +1684,23 @@ func newDefaultLogContainer() corev1.Container {
 	}
 }
 
+// newDebugContainer returns debug sidecar Container, which idles and is expected to be used via 'kubectl exec'.
+// Container has minimal resources with limits equal to requests, so it does not spoil Guaranteed QoS class of the pod
+func newDebugContainer(image string, pullPolicy corev1.PullPolicy) corev1.Container {
+	return corev1.Container{
+		Name:            ClickHouseDebugContainerName,
+		Image:           image,
+		ImagePullPolicy: pullPolicy,
+		Resources:       newSidecarResources(),
+		Command: []string{
+			"/bin/sh", "-c", "--",
+		},
+		Args: []string{
+			"while true; do sleep 30; done;",
+		},
+	}
+}
+
 // addContainer adds container to ChiPodTemplate
 func addContainer(podSpec *corev1.PodSpec, container corev1.Container) {
 	podSpec.Containers = append(podSpec.Containers, container)
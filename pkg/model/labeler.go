@@ -50,12 +50,14 @@ const (
 	labelServiceValueCluster          = "cluster"
 	labelServiceValueShard            = "shard"
 	labelServiceValueHost             = "host"
+	labelServiceValueHostMetrics      = "host-metrics"
 	LabelPVCReclaimPolicyName         = clickhousealtinitycom.GroupName + "/reclaimPolicy"
 
 	// Supplementary service labels - used to cooperate with k8s
 
 	LabelZookeeperConfigVersion = clickhousealtinitycom.GroupName + "/zookeeper-version"
 	LabelSettingsConfigVersion  = clickhousealtinitycom.GroupName + "/settings-version"
+	LabelSecretConfigVersion    = clickhousealtinitycom.GroupName + "/secret-version"
 	LabelObjectVersion          = clickhousealtinitycom.GroupName + "/object-version"
 
 	// Optional labels
@@ -147,6 +149,15 @@ func (l *Labeler) getServiceHost(host *chiv1.ChiHost) map[string]string {
 		})
 }
 
+// getServiceHostMetrics
+func (l *Labeler) getServiceHostMetrics(host *chiv1.ChiHost) map[string]string {
+	return util.MergeStringMapsOverwrite(
+		l.getHostScope(host, false),
+		map[string]string{
+			LabelService: labelServiceValueHostMetrics,
+		})
+}
+
 // getCHIScope gets labels for CHI-scoped object
 func (l *Labeler) getCHIScope() map[string]string {
 	// Combine generated labels and CHI-provided labels
@@ -238,10 +249,21 @@ func (l *Labeler) getHostScope(host *chiv1.ChiHost, applySupplementaryServiceLab
 		// When we'll have Cluster Discovery functionality we can refactor this properly
 		labels[LabelZookeeperConfigVersion] = host.Config.ZookeeperFingerprint
 		labels[LabelSettingsConfigVersion] = util.Fingerprint(host.Config.SettingsFingerprint + host.Config.FilesFingerprint)
+		// All hosts of the cluster share the same secret fingerprint, so secret rotation restarts all of them.
+		// Hosts without secret are not labeled, so their pod templates are not changed
+		if host.Config.SecretFingerprint != "" {
+			labels[LabelSecretConfigVersion] = host.Config.SecretFingerprint
+		}
 	}
 	return l.appendCHIProvidedTo(labels)
 }
 
+// getStatefulSet gets labels for host's StatefulSet, including additional labels from operator's config.
+// Operator's own labels can not be overwritten by additional labels
+func (l *Labeler) getStatefulSet(host *chiv1.ChiHost) map[string]string {
+	return util.MergeStringMapsPreserve(l.getHostScope(host, true), chop.Config().StatefulSetLabels)
+}
+
 // getHostScopeReady gets labels for Host-scoped object including Ready label
 func (l *Labeler) getHostScopeReady(host *chiv1.ChiHost, applySupplementaryServiceLabels bool) map[string]string {
 	return appendReady(l.getHostScope(host, applySupplementaryServiceLabels))
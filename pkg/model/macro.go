@@ -25,6 +25,8 @@ import (
 const (
 	// macrosNamespace is a sanitized namespace name where ClickHouseInstallation runs
 	macrosNamespace = "{namespace}"
+	// macrosNamespaceID is a short ID made of original namespace name
+	macrosNamespaceID = "{namespaceID}"
 
 	// macrosChiName is a sanitized ClickHouseInstallation name
 	macrosChiName = "{chi}"
@@ -138,6 +140,7 @@ func (m *macrosEngine) Map(_map map[string]string) map[string]string {
 func (m *macrosEngine) newLineMacroReplacerChi() *strings.Replacer {
 	return strings.NewReplacer(
 		macrosNamespace, m.names.namePartNamespace(m.chi.Namespace),
+		macrosNamespaceID, m.names.namePartNamespaceID(m.chi.Namespace),
 		macrosChiName, m.names.namePartChiName(m.chi.Name),
 		macrosChiID, m.names.namePartChiNameID(m.chi.Name),
 	)
@@ -152,6 +155,7 @@ func (m *macrosEngine) newMapMacroReplacerChi() *util.MapReplacer {
 func (m *macrosEngine) newLineMacroReplacerCluster() *strings.Replacer {
 	return strings.NewReplacer(
 		macrosNamespace, m.names.namePartNamespace(m.cluster.Address.Namespace),
+		macrosNamespaceID, m.names.namePartNamespaceID(m.cluster.Address.Namespace),
 		macrosChiName, m.names.namePartChiName(m.cluster.Address.CHIName),
 		macrosChiID, m.names.namePartChiNameID(m.cluster.Address.CHIName),
 		macrosClusterName, m.names.namePartClusterName(m.cluster.Address.ClusterName),
@@ -169,6 +173,7 @@ func (m *macrosEngine) newMapMacroReplacerCluster() *util.MapReplacer {
 func (m *macrosEngine) newLineMacroReplacerShard() *strings.Replacer {
 	return strings.NewReplacer(
 		macrosNamespace, m.names.namePartNamespace(m.shard.Address.Namespace),
+		macrosNamespaceID, m.names.namePartNamespaceID(m.shard.Address.Namespace),
 		macrosChiName, m.names.namePartChiName(m.shard.Address.CHIName),
 		macrosChiID, m.names.namePartChiNameID(m.shard.Address.CHIName),
 		macrosClusterName, m.names.namePartClusterName(m.shard.Address.ClusterName),
@@ -211,6 +216,7 @@ func clusterScopeIndexOfPreviousCycleTail(host *chop.ChiHost) int {
 func (m *macrosEngine) newLineMacroReplacerHost() *strings.Replacer {
 	return strings.NewReplacer(
 		macrosNamespace, m.names.namePartNamespace(m.host.Address.Namespace),
+		macrosNamespaceID, m.names.namePartNamespaceID(m.host.Address.Namespace),
 		macrosChiName, m.names.namePartChiName(m.host.Address.CHIName),
 		macrosChiID, m.names.namePartChiNameID(m.host.Address.CHIName),
 		macrosClusterName, m.names.namePartClusterName(m.host.Address.ClusterName),
@@ -38,6 +38,14 @@ const (
 	namePartClusterMaxLenLabelsCtx = 63
 	namePartShardMaxLenLabelsCtx   = 63
 	namePartReplicaMaxLenLabelsCtx = 63
+
+	// Namespace ID length, short enough to be appended to names and macros within length limits
+	namePartNamespaceIDLen = 6
+
+	// Max length of a DNS label, which Service name has to fit into
+	dnsLabelMaxLen = 63
+	// Length of ID, which replaces tail of a name too long to fit into length limit
+	nameIDLen = 8
 )
 
 const (
@@ -59,6 +67,12 @@ const (
 	// statefulSetServiceNamePattern is a template of hosts's StatefulSet's Service name. "chi-{chi}-{cluster}-{shard}-{host}"
 	statefulSetServiceNamePattern = "chi-" + macrosChiName + "-" + macrosClusterName + "-" + macrosHostName
 
+	// hostMetricsServiceNamePattern is a template of hosts's metrics-only Service name. "chi-{chi}-{cluster}-{host}"
+	// Name is suffixed with hostMetricsServiceNameSuffix. "chi-{chi}-{cluster}-{host}-metrics"
+	hostMetricsServiceNamePattern = "chi-" + macrosChiName + "-" + macrosClusterName + "-" + macrosHostName
+	// hostMetricsServiceNameSuffix is a suffix of hosts's metrics-only Service name
+	hostMetricsServiceNameSuffix = "-metrics"
+
 	// configMapCommonNamePattern is a template of common settings for the CHI ConfigMap. "chi-{chi}-common-configd"
 	configMapCommonNamePattern = "chi-" + macrosChiName + "-common-configd"
 
@@ -88,6 +102,16 @@ const (
 	podNamePattern = "%s-0"
 )
 
+// shortenName makes name fit into maxLen.
+// Tail of a longer name is replaced with name's ID, so shortened names of different objects do not collide
+func shortenName(name string, maxLen int) string {
+	if len(name) <= maxLen {
+		return name
+	}
+	id := util.CreateStringID(name, nameIDLen)
+	return sanitize(util.StringHead(name, maxLen-len(id)-1)) + "-" + id
+}
+
 // sanitize makes string fulfil kubernetes naming restrictions
 // String can't end with '-', '_' and '.'
 func sanitize(s string) string {
@@ -122,6 +146,11 @@ func (n *namer) namePartNamespace(name string) string {
 	return sanitize(util.StringHead(name, _len))
 }
 
+// namePartNamespaceID
+func (n *namer) namePartNamespaceID(name string) string {
+	return util.CreateStringID(name, namePartNamespaceIDLen)
+}
+
 // namePartChiName
 func (n *namer) namePartChiName(name string) string {
 	var _len int
@@ -378,6 +407,11 @@ func CreateConfigMapCommonUsersName(chi *chop.ClickHouseInstallation) string {
 	return macro(chi).Line(configMapCommonUsersNamePattern)
 }
 
+// CreatePodMonitorName creates a name of a PodMonitor of the ClickHouseInstallation
+func CreatePodMonitorName(chi *chop.ClickHouseInstallation) string {
+	return chi.Name
+}
+
 // CreateCHIServiceName creates a name of a root ClickHouseInstallation Service resource
 func CreateCHIServiceName(chi *chop.ClickHouseInstallation) string {
 	// Name can be generated either from default name pattern,
@@ -401,6 +435,21 @@ func CreateCHIServiceName(chi *chop.ClickHouseInstallation) string {
 
 // CreateCHIServiceFQDN creates a FQD name of a root ClickHouseInstallation Service resource
 func CreateCHIServiceFQDN(chi *chop.ClickHouseInstallation) string {
+	return createServiceFQDN(chi, CreateCHIServiceName(chi), chi.Namespace)
+}
+
+// CreateClusterServiceFQDN creates a FQD name of a cluster's Service
+func CreateClusterServiceFQDN(cluster *chop.ChiCluster) string {
+	return createServiceFQDN(cluster.GetCHI(), CreateClusterServiceName(cluster), cluster.Address.Namespace)
+}
+
+// CreateShardServiceFQDN creates a FQD name of a shard's Service
+func CreateShardServiceFQDN(shard *chop.ChiShard) string {
+	return createServiceFQDN(shard.GetCHI(), CreateShardServiceName(shard), shard.Address.Namespace)
+}
+
+// createServiceFQDN creates a FQD name of a Service with specified name
+func createServiceFQDN(chi *chop.ClickHouseInstallation, serviceName, namespace string) string {
 	// FQDN can be generated either from default pattern,
 	// or from personal pattern provided
 
@@ -415,11 +464,34 @@ func CreateCHIServiceFQDN(chi *chop.ClickHouseInstallation) string {
 	// Create FQDN based on pattern available
 	return fmt.Sprintf(
 		pattern,
-		CreateCHIServiceName(chi),
-		chi.Namespace,
+		serviceName,
+		namespace,
 	)
 }
 
+// CHIEndpoints returns FQD names of client-facing Services of the CHI.
+// CHI Service goes first, followed by cluster Services, which are created in case either ServiceTemplate is specified
+// or default cluster Service is enabled in operator's config, followed by shard Services,
+// which are created only in case ServiceTemplate is specified
+func CHIEndpoints(chi *chop.ClickHouseInstallation) []string {
+	endpoints := []string{
+		CreateCHIServiceFQDN(chi),
+	}
+	chi.WalkClusters(func(cluster *chop.ChiCluster) error {
+		if hasServiceCluster(cluster) {
+			endpoints = append(endpoints, CreateClusterServiceFQDN(cluster))
+		}
+		return nil
+	})
+	chi.WalkShards(func(shard *chop.ChiShard) error {
+		if _, ok := shard.GetServiceTemplate(); ok {
+			endpoints = append(endpoints, CreateShardServiceFQDN(shard))
+		}
+		return nil
+	})
+	return endpoints
+}
+
 // CreateClusterServiceName returns a name of a cluster's Service
 func CreateClusterServiceName(cluster *chop.ChiCluster) string {
 	// Name can be generated either from default name pattern,
@@ -581,6 +653,13 @@ func CreateStatefulSetServiceName(host *chop.ChiHost) string {
 	return macro(host).Line(pattern)
 }
 
+// CreateHostMetricsServiceName returns a name of a metrics-only Service of a ClickHouse instance
+// Name has to fit into DNS label length limit, so it is shortened in case of long CHI and cluster names
+func CreateHostMetricsServiceName(host *chop.ChiHost) string {
+	name := macro(host).Line(hostMetricsServiceNamePattern)
+	return shortenName(name, dnsLabelMaxLen-len(hostMetricsServiceNameSuffix)) + hostMetricsServiceNameSuffix
+}
+
 // CreatePodHostname returns a name of a Pod of a ClickHouse instance
 func CreatePodHostname(host *chop.ChiHost) string {
 	// Do not use Pod own hostname - point to appropriate StatefulSet's Service
@@ -675,6 +754,22 @@ func CreateFQDNs(obj interface{}, scope interface{}, excludeSelf bool) []string
 	return nil
 }
 
+// CreateReplicaID creates stable identity of a host, derived from its address only.
+// It does not depend on name patterns, so it stays the same across reconciles as long as the host keeps its place
+func CreateReplicaID(host *chop.ChiHost) string {
+	address := strings.Join(
+		[]string{
+			host.Address.Namespace,
+			host.Address.CHIName,
+			host.Address.ClusterName,
+			host.Address.ShardName,
+			host.Address.ReplicaName,
+		},
+		"/",
+	)
+	return util.HashIntoString([]byte(address))
+}
+
 // CreatePodRegexp creates pod regexp
 // template is defined in operator config:
 // CHConfigNetworksHostRegexpTemplate: chi-{chi}-[^.]+\\d+-\\d+\\.{namespace}.svc.cluster.local$"
@@ -157,6 +157,10 @@ func (n *Normalizer) normalize() (*chiV1.ClickHouseInstallation, error) {
 func (n *Normalizer) finalizeCHI() {
 	n.chi.FillSelfCalculatedAddressInfo()
 	n.chi.FillCHIPointer()
+	n.chi.WalkClusters(func(cluster *chiV1.ChiCluster) error {
+		n.expandClusterZookeeperRoot(cluster)
+		return nil
+	})
 	n.chi.WalkHosts(func(host *chiV1.ChiHost) error {
 		hostTemplate := n.getHostTemplate(host)
 		hostApplyHostTemplate(host, hostTemplate)
@@ -303,6 +307,9 @@ func ensurePortValuesFromSettings(host *chiV1.ChiHost, settings *chiV1.Settings,
 	ensurePortValue(&host.TCPPort, settings.GetTCPPort(), fallbackTCPPortNumber)
 	ensurePortValue(&host.HTTPPort, settings.GetHTTPPort(), fallbackHTTPPortNumber)
 	ensurePortValue(&host.InterserverHTTPPort, settings.GetInterserverHTTPPort(), fallbackInterserverHTTPPortNumber)
+	// MySQL and PostgreSQL protocols are disabled unless port is specified explicitly
+	ensurePortValue(&host.MySQLPort, settings.GetMySQLPort(), chPortNumberMustBeAssignedLater)
+	ensurePortValue(&host.PostgreSQLPort, settings.GetPostgreSQLPort(), chPortNumberMustBeAssignedLater)
 }
 
 // ensurePortValue
@@ -326,7 +333,8 @@ func ensurePortValue(port *int32, value, _default int32) {
 
 // fillStatus fills .status section of a CHI with values based on current CHI
 func (n *Normalizer) fillStatus() {
-	endpoint := CreateCHIServiceFQDN(n.chi)
+	// CHI Service is the main client endpoint
+	endpoint := CHIEndpoints(n.chi)[0]
 	pods := make([]string, 0)
 	fqdns := make([]string, 0)
 	n.chi.WalkHosts(func(host *chiV1.ChiHost) error {
@@ -422,7 +430,8 @@ func (n *Normalizer) normalizeConfiguration(conf *chiV1.Configuration) *chiV1.Co
 		conf = chiV1.NewConfiguration()
 	}
 	conf.Zookeeper = n.normalizeConfigurationZookeeper(conf.Zookeeper)
-	conf.Users = n.normalizeConfigurationUsers(conf.Users)
+	conf.UserTemplates = n.normalizeConfigurationUserTemplates(conf.UserTemplates)
+	conf.Users = n.normalizeConfigurationUsers(conf.Users, conf.UserTemplates)
 	conf.Profiles = n.normalizeConfigurationProfiles(conf.Profiles)
 	conf.Quotas = n.normalizeConfigurationQuotas(conf.Quotas)
 	conf.Settings = n.normalizeConfigurationSettings(conf.Settings)
@@ -780,6 +789,11 @@ func (n *Normalizer) ensureClusters(clusters []*chiV1.ChiCluster) []*chiV1.ChiCl
 func (n *Normalizer) calcFingerprints(host *chiV1.ChiHost) error {
 	zk := host.GetZookeeper()
 	host.Config.ZookeeperFingerprint = util.Fingerprint(zk)
+	host.Config.SecretFingerprint = ""
+	if secret := host.GetCluster().Secret.GetResolved(); secret != "" {
+		// Salted with CHI UID, so label value can not be matched against fingerprints of well-known secrets
+		host.Config.SecretFingerprint = util.Fingerprint(fmt.Sprintf("%s%s", n.chi.UID, secret))
+	}
 
 	global := n.chi.Spec.Configuration.Settings.AsSortedSliceOfStrings()
 	local := host.Settings.AsSortedSliceOfStrings()
@@ -834,6 +848,15 @@ func (n *Normalizer) normalizeConfigurationZookeeper(zk *chiV1.ChiZookeeperConfi
 	return zk
 }
 
+// expandClusterZookeeperRoot expands macros in ZK root of the cluster,
+// so clusters sharing one ZK ensemble can have distinct roots, ex.: '/clickhouse/{chi}/{cluster}'
+func (n *Normalizer) expandClusterZookeeperRoot(cluster *chiV1.ChiCluster) {
+	if cluster.Zookeeper == nil {
+		return
+	}
+	cluster.Zookeeper.Root = macro(cluster).Line(cluster.Zookeeper.Root)
+}
+
 // substWithSecretField substitute users settings field with value from k8s secret
 func (n *Normalizer) substWithSecretField(users *chiV1.Settings, username string, userSettingsField, userSettingsK8SSecretField string) {
 	// Has to have source field specified
@@ -868,23 +891,27 @@ func (n *Normalizer) substWithSecretField(users *chiV1.Settings, username string
 		return
 	}
 
+	if value, ok := n.fetchSecretField(namespace, name, field); ok {
+		users.Set(username+"/"+userSettingsField, chiV1.NewSettingScalar(value))
+	}
+}
+
+// fetchSecretField reads value of specified field of k8s secret
+func (n *Normalizer) fetchSecretField(namespace, name, field string) (string, bool) {
 	secret, err := n.kubeClient.CoreV1().Secrets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
 	if err != nil {
 		log.V(1).M(namespace, name).F().Info("unable to read secret %v", err)
-		return
+		return "", false
 	}
 
-	found := false
 	for key, value := range secret.Data {
 		if key == field {
-			users.Set(username+"/"+userSettingsField, chiV1.NewSettingScalar(string(value)))
-			found = true
+			return string(value), true
 		}
 	}
 
-	if !found {
-		log.V(1).M(namespace, name).F().Info("unable to locate in specified secret field %s", field)
-	}
+	log.V(1).M(namespace, name).F().Info("unable to locate in specified secret field %s", field)
+	return "", false
 }
 
 // normalizeUsersList extracts usernames from provided 'users' settings
@@ -914,7 +941,7 @@ func (n *Normalizer) normalizeUsersList(users *chiV1.Settings) (usernames []stri
 const defaultUsername = "default"
 
 // normalizeConfigurationUsers normalizes .spec.configuration.users
-func (n *Normalizer) normalizeConfigurationUsers(users *chiV1.Settings) *chiV1.Settings {
+func (n *Normalizer) normalizeConfigurationUsers(users, userTemplates *chiV1.Settings) *chiV1.Settings {
 	if users == nil {
 		users = chiV1.NewSettings()
 	}
@@ -923,6 +950,9 @@ func (n *Normalizer) normalizeConfigurationUsers(users *chiV1.Settings) *chiV1.S
 	usernames := append(n.normalizeUsersList(users), defaultUsername)
 	// Normalize each user
 	for _, username := range usernames {
+		// Expand user template, if any. Explicitly specified user fields have priority over template's ones
+		n.expandUserTemplate(users, username, userTemplates)
+
 		// Ensure "must have" sections are in place
 		// 1. user/profile
 		// 2. user/quota
@@ -974,6 +1004,44 @@ func (n *Normalizer) normalizeConfigurationUsers(users *chiV1.Settings) *chiV1.S
 	return users
 }
 
+// normalizeConfigurationUserTemplates normalizes .spec.configuration.userTemplates
+func (n *Normalizer) normalizeConfigurationUserTemplates(userTemplates *chiV1.Settings) *chiV1.Settings {
+	if userTemplates == nil {
+		return nil
+	}
+	userTemplates.Normalize()
+	return userTemplates
+}
+
+// userTemplateField specifies name of user's field, which refers user template to inherit
+const userTemplateField = "template"
+
+// expandUserTemplate fills user with fields of user template referred by 'username/template',
+// in case user does not have these fields specified explicitly
+func (n *Normalizer) expandUserTemplate(users *chiV1.Settings, username string, userTemplates *chiV1.Settings) {
+	path := username + "/" + userTemplateField
+	if !users.Has(path) {
+		return
+	}
+
+	// Template reference is not a ClickHouse user's field and should not be rendered into users config
+	templateName := users.Get(path).String()
+	users.Delete(path)
+
+	prefix := templateName + "/"
+	found := false
+	userTemplates.Walk(func(name string, setting *chiV1.Setting) {
+		if strings.HasPrefix(name, prefix) {
+			found = true
+			users.SetIfNotExists(username+"/"+strings.TrimPrefix(name, prefix), setting)
+		}
+	})
+
+	if !found {
+		log.V(1).M(n.chi).F().Warning("user: %s refers unknown user template: %s", username, templateName)
+	}
+}
+
 // normalizeConfigurationProfiles normalizes .spec.configuration.profiles
 func (n *Normalizer) normalizeConfigurationProfiles(profiles *chiV1.Settings) *chiV1.Settings {
 	if profiles == nil {
@@ -1001,9 +1069,58 @@ func (n *Normalizer) normalizeConfigurationSettings(settings *chiV1.Settings) *c
 		return nil
 	}
 	settings.Normalize()
+	n.normalizeConfigurationSettingsPrometheus(settings)
+	n.normalizeConfigurationSettingsDataPath(settings)
 	return settings
 }
 
+// dataPathSubfolders maps settings of folders, which default config has hardcoded within default data folder,
+// to their subfolders within data folder
+var dataPathSubfolders = map[string]string{
+	configSettingTmpPath:          "tmp/",
+	configSettingUserFilesPath:    "user_files/",
+	configSettingFormatSchemaPath: "format_schemas/",
+	configSettingAccessPath:       "access/",
+}
+
+// normalizeConfigurationSettingsDataPath keeps folders for temporary data, user files, format schemas and
+// access entities within data folder, in case data folder is explicitly specified.
+// Default config has these folders hardcoded within default data folder, which would not be on the data volume otherwise
+func (n *Normalizer) normalizeConfigurationSettingsDataPath(settings *chiV1.Settings) {
+	if !settings.Has(configSettingPath) {
+		// Default data folder is used
+		return
+	}
+	path := strings.TrimSuffix(settings.Get(configSettingPath).String(), "/")
+	if path == "" {
+		return
+	}
+	for setting, subfolder := range dataPathSubfolders {
+		settings.SetIfNotExists(setting, chiV1.NewSettingScalar(path+"/"+subfolder))
+	}
+}
+
+// prometheusToggles lists metric families exposed by ClickHouse prometheus endpoint
+var prometheusToggles = []string{
+	"metrics",
+	"events",
+	"asynchronous_metrics",
+	"status_info",
+}
+
+// normalizeConfigurationSettingsPrometheus enables all metric families of prometheus endpoint,
+// in case endpoint is configured and metric family is not explicitly specified.
+// ClickHouse does not expose most of the families unless explicitly enabled
+func (n *Normalizer) normalizeConfigurationSettingsPrometheus(settings *chiV1.Settings) {
+	if !settings.Has("prometheus/endpoint") && !settings.Has("prometheus/port") {
+		// Prometheus endpoint is not configured
+		return
+	}
+	for _, toggle := range prometheusToggles {
+		settings.SetIfNotExists("prometheus/"+toggle, chiV1.NewSettingScalar("true"))
+	}
+}
+
 // normalizeConfigurationFiles normalizes .spec.configuration.files
 func (n *Normalizer) normalizeConfigurationFiles(files *chiV1.Settings) *chiV1.Settings {
 	if files == nil {
@@ -1014,6 +1131,30 @@ func (n *Normalizer) normalizeConfigurationFiles(files *chiV1.Settings) *chiV1.S
 	return files
 }
 
+// normalizeClusterSecret normalizes cluster secret.
+// Secret value is resolved once per CHI, so all replicas of the cluster get the same value in one reconcile
+func (n *Normalizer) normalizeClusterSecret(secret *chiV1.ChiClusterSecret) *chiV1.ChiClusterSecret {
+	if secret == nil {
+		return nil
+	}
+
+	if !secret.HasSecretKeyRef() {
+		// Plaintext value
+		secret.Resolved = secret.Value
+		return secret
+	}
+
+	// Value from k8s secret has higher priority and does not fall back to plaintext value.
+	// Unresolved secret is reported by validation, so reconcile does not start with wrong secret
+	secret.Resolved = ""
+	ref := secret.ValueFrom.SecretKeyRef
+	if value, ok := n.fetchSecretField(n.chi.Namespace, ref.Name, ref.Key); ok {
+		secret.Resolved = value
+	}
+
+	return secret
+}
+
 // normalizeCluster normalizes cluster and returns deployments usage counters for this cluster
 func (n *Normalizer) normalizeCluster(cluster *chiV1.ChiCluster) *chiV1.ChiCluster {
 	if cluster == nil {
@@ -1030,6 +1171,7 @@ func (n *Normalizer) normalizeCluster(cluster *chiV1.ChiCluster) *chiV1.ChiClust
 	cluster.Zookeeper = n.normalizeConfigurationZookeeper(cluster.Zookeeper)
 	cluster.Settings = n.normalizeConfigurationSettings(cluster.Settings)
 	cluster.Files = n.normalizeConfigurationFiles(cluster.Files)
+	cluster.Secret = n.normalizeClusterSecret(cluster.Secret)
 
 	if cluster.Layout == nil {
 		cluster.Layout = chiV1.NewChiClusterLayout()
@@ -1323,6 +1465,9 @@ func (n *Normalizer) normalizeHost(
 	host.InheritFilesFrom(s, r)
 	host.Files = n.normalizeConfigurationSettings(host.Files)
 	host.InheritTemplatesFrom(s, r, nil)
+	host.InheritDataVolumeStorageFrom(r)
+	host.InheritPriorityFrom(r)
+	host.InheritPartitionFrom(r)
 }
 
 // normalizeHostTemplateSpec is the same as normalizeHost but for a template
@@ -1364,6 +1509,14 @@ func (n *Normalizer) normalizeHostPorts(host *chiV1.ChiHost) {
 	if (host.InterserverHTTPPort <= 0) || (host.InterserverHTTPPort >= 65535) {
 		host.InterserverHTTPPort = chPortNumberMustBeAssignedLater
 	}
+
+	if (host.MySQLPort <= 0) || (host.MySQLPort >= 65535) {
+		host.MySQLPort = chPortNumberMustBeAssignedLater
+	}
+
+	if (host.PostgreSQLPort <= 0) || (host.PostgreSQLPort >= 65535) {
+		host.PostgreSQLPort = chPortNumberMustBeAssignedLater
+	}
 }
 
 // normalizeShardInternalReplication ensures reasonable values in
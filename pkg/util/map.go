@@ -217,6 +217,16 @@ func MapHasKeys(m map[string]string, keys ...string) bool {
 	return true
 }
 
+// StringMapToInterfaceMap converts map[string]string into map[string]interface{},
+// as expected by unstructured objects
+func StringMapToInterfaceMap(m map[string]string) map[string]interface{} {
+	res := make(map[string]interface{}, len(m))
+	for key, value := range m {
+		res[key] = value
+	}
+	return res
+}
+
 // Map2String returns named map[string]string mas as a string
 func Map2String(name string, m map[string]string) string {
 	// Write map entries according to sorted keys